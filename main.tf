@@ -28,7 +28,7 @@ resource "gns3_qemu_node" "csr1" {
   ram            = 8194
   mac_address    = "00:1b:54:cc:dd:e1" 
   platform       = "x86_64"
-  start_vm       = true
+  status         = "started"
 
   # Position on GNS3 canvas
   x = 200
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// cloudInitSchema describes the cloud_init block of QEMU nodes.
+func cloudInitSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"cdrom_image"},
+		Description:   "NoCloud seed built into an ISO (label cidata) and attached as the node's CD-ROM.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user_data": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Content of user-data, e.g. a #cloud-config document.",
+				},
+				"meta_data": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Content of meta-data. Defaults to an instance-id and local-hostname derived from the node name and content.",
+				},
+				"network_config": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Content of network-config (version 1 or 2). Left out of the ISO when empty.",
+				},
+			},
+		},
+	}
+}
+
+// cloudInitConfig is the content of a cloud_init block.
+type cloudInitConfig struct {
+	UserData      string
+	MetaData      string
+	NetworkConfig string
+}
+
+// getCloudInitConfig reads the cloud_init block. ok is false when the block is absent.
+func getCloudInitConfig(raw interface{}) (cloudInitConfig, bool) {
+	blocks, _ := raw.([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return cloudInitConfig{}, false
+	}
+	block := blocks[0].(map[string]interface{})
+	return cloudInitConfig{
+		UserData:      block["user_data"].(string),
+		MetaData:      block["meta_data"].(string),
+		NetworkConfig: block["network_config"].(string),
+	}, true
+}
+
+// cloudInitFiles returns the NoCloud seed files. Without meta_data the instance-id
+// changes with the content, so cloud-init runs again when the seed changes.
+func cloudInitFiles(nodeName string, c cloudInitConfig) map[string][]byte {
+	files := map[string][]byte{
+		"user-data": []byte(c.UserData),
+	}
+	if c.NetworkConfig != "" {
+		files["network-config"] = []byte(c.NetworkConfig)
+	}
+
+	metaData := c.MetaData
+	if metaData == "" {
+		sum := sha256.Sum256([]byte(nodeName + "\x00" + c.UserData + "\x00" + c.NetworkConfig))
+		metaData = fmt.Sprintf("instance-id: %s-%s\nlocal-hostname: %s\n", nodeName, hex.EncodeToString(sum[:])[:8], nodeName)
+	}
+	files["meta-data"] = []byte(metaData)
+	return files
+}
+
+// cloudInitISOName returns the content-addressed image name of a seed, so a change
+// of content yields a new cdrom_image and an unchanged seed is never re-uploaded.
+func cloudInitISOName(nodeName string, files map[string][]byte) string {
+	h := sha256.New()
+	for _, name := range []string{"meta-data", "network-config", "user-data"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(content))
+		h.Write(content)
+	}
+	slug := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, nodeName)
+	return fmt.Sprintf("%s-cidata-%s.iso", slug, hex.EncodeToString(h.Sum(nil))[:12])
+}
+
+// uploadCloudInitISO builds the seed ISO and uploads it, through the controller, to the
+// QEMU image store of the node's compute unless it already has it. It returns the
+// image name to use as cdrom_image.
+func uploadCloudInitISO(host, computeID, nodeName string, c cloudInitConfig) (string, error) {
+	files := cloudInitFiles(nodeName, c)
+	filename := cloudInitISOName(nodeName, files)
+
+	if _, found, err := findImage(host, computeID, "qemu", filename); err != nil {
+		return "", err
+	} else if found {
+		return filename, nil
+	}
+
+	iso, err := buildISO("cidata", files)
+	if err != nil {
+		return "", fmt.Errorf("failed to build cloud-init ISO: %s", err)
+	}
+	if _, err := uploadImage(host, computeID, "qemu", filename, bytes.NewReader(iso)); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCloudInitFiles(t *testing.T) {
+	files := cloudInitFiles("r1", cloudInitConfig{UserData: "#cloud-config\n"})
+	if _, ok := files["network-config"]; ok {
+		t.Error("network-config should be left out when empty")
+	}
+	meta := string(files["meta-data"])
+	if !regexp.MustCompile(`^instance-id: r1-[0-9a-f]{8}\nlocal-hostname: r1\n$`).MatchString(meta) {
+		t.Errorf("default meta-data = %q", meta)
+	}
+
+	changed := cloudInitFiles("r1", cloudInitConfig{UserData: "#cloud-config\nhostname: r1\n"})
+	if string(changed["meta-data"]) == meta {
+		t.Error("default instance-id should change with user-data")
+	}
+
+	explicit := cloudInitFiles("r1", cloudInitConfig{
+		UserData:      "#cloud-config\n",
+		MetaData:      "instance-id: fixed\n",
+		NetworkConfig: "version: 2\n",
+	})
+	if string(explicit["meta-data"]) != "instance-id: fixed\n" {
+		t.Errorf("meta-data = %q, want it as written", explicit["meta-data"])
+	}
+	if string(explicit["network-config"]) != "version: 2\n" {
+		t.Errorf("network-config = %q, want it as written", explicit["network-config"])
+	}
+}
+
+func TestCloudInitISOName(t *testing.T) {
+	c := cloudInitConfig{UserData: "#cloud-config\n", NetworkConfig: "version: 2\n"}
+	name := cloudInitISOName("edge r1.lab", cloudInitFiles("edge r1.lab", c))
+	if !regexp.MustCompile(`^edge_r1_lab-cidata-[0-9a-f]{12}\.iso$`).MatchString(name) {
+		t.Errorf("name = %q", name)
+	}
+	if again := cloudInitISOName("edge r1.lab", cloudInitFiles("edge r1.lab", c)); again != name {
+		t.Errorf("name changed between runs: %q then %q", name, again)
+	}
+
+	c.NetworkConfig = "version: 1\n"
+	if other := cloudInitISOName("edge r1.lab", cloudInitFiles("edge r1.lab", c)); other == name {
+		t.Error("name should change with the content")
+	}
+
+	// Pinned so a change to the hashing, which would re-upload every seed, is noticed
+	pinned := cloudInitISOName("r1", cloudInitFiles("r1", cloudInitConfig{UserData: "#cloud-config\n"}))
+	if pinned != "r1-cidata-23e1c79c8c14.iso" {
+		t.Errorf("name = %q, want r1-cidata-23e1c79c8c14.iso", pinned)
+	}
+}
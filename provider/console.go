@@ -0,0 +1,294 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Telnet protocol bytes (RFC 854)
+const (
+	telnetIAC  = 255
+	telnetDONT = 254
+	telnetDO   = 253
+	telnetWONT = 252
+	telnetWILL = 251
+	telnetSB   = 250
+	telnetSE   = 240
+)
+
+// consoleSession is a minimal telnet client for GNS3 node consoles. It refuses every
+// option the server negotiates, which leaves the console in plain line mode.
+type consoleSession struct {
+	conn  net.Conn
+	out   bytes.Buffer
+	state int
+	cmd   byte
+}
+
+// Telnet parser states
+const (
+	telnetStateData = iota
+	telnetStateIAC
+	telnetStateOption
+	telnetStateSub
+	telnetStateSubIAC
+)
+
+func dialConsole(addr string, timeout time.Duration) (*consoleSession, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to console %s: %s", addr, err)
+	}
+	return &consoleSession{conn: conn}, nil
+}
+
+// openNodeConsole dials the telnet console of a node, retrying until the port accepts connections.
+func openNodeConsole(host string, node map[string]interface{}, timeout time.Duration) (*consoleSession, error) {
+	if ct, _ := node["console_type"].(string); ct != "" && ct != "telnet" {
+		return nil, fmt.Errorf("node %v uses console type %q, only telnet consoles are supported", node["name"], ct)
+	}
+	addr, err := consoleAddress(host, node)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		session, err := dialConsole(addr, 5*time.Second)
+		if err == nil {
+			return session, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func (s *consoleSession) Close() error {
+	return s.conn.Close()
+}
+
+// send writes a line terminated by CR LF to the console.
+func (s *consoleSession) send(line string) error {
+	_, err := s.conn.Write([]byte(line + "\r\n"))
+	return err
+}
+
+// filter strips telnet commands from data and answers option negotiation.
+func (s *consoleSession) filter(data []byte) []byte {
+	clean := make([]byte, 0, len(data))
+	var replies []byte
+
+	for _, b := range data {
+		switch s.state {
+		case telnetStateData:
+			if b == telnetIAC {
+				s.state = telnetStateIAC
+			} else {
+				clean = append(clean, b)
+			}
+		case telnetStateIAC:
+			switch b {
+			case telnetIAC:
+				clean = append(clean, b)
+				s.state = telnetStateData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				s.cmd = b
+				s.state = telnetStateOption
+			case telnetSB:
+				s.state = telnetStateSub
+			default:
+				s.state = telnetStateData
+			}
+		case telnetStateOption:
+			switch s.cmd {
+			case telnetWILL:
+				replies = append(replies, telnetIAC, telnetDONT, b)
+			case telnetDO:
+				replies = append(replies, telnetIAC, telnetWONT, b)
+			}
+			s.state = telnetStateData
+		case telnetStateSub:
+			if b == telnetIAC {
+				s.state = telnetStateSubIAC
+			}
+		case telnetStateSubIAC:
+			if b == telnetSE {
+				s.state = telnetStateData
+			} else {
+				s.state = telnetStateSub
+			}
+		}
+	}
+
+	if len(replies) > 0 {
+		_, _ = s.conn.Write(replies)
+	}
+	return clean
+}
+
+// expect reads console output until pattern matches or the timeout expires. The output
+// up to the end of the match is returned; anything after it is kept for the next call.
+// When nudge is set an empty line is sent periodically to make the device print a prompt.
+func (s *consoleSession) expect(pattern *regexp.Regexp, timeout time.Duration, nudge bool) (string, error) {
+	deadline := time.Now().Add(timeout)
+	lastNudge := time.Now()
+	buf := make([]byte, 4096)
+
+	for {
+		if loc := pattern.FindIndex(s.out.Bytes()); loc != nil {
+			return string(s.out.Next(loc[1])), nil
+		}
+		if time.Now().After(deadline) {
+			return s.out.String(), fmt.Errorf("timed out after %s waiting for console pattern %q", timeout, pattern.String())
+		}
+
+		if nudge && time.Since(lastNudge) > 5*time.Second {
+			if err := s.send(""); err != nil {
+				return s.out.String(), fmt.Errorf("failed to write to console: %s", err)
+			}
+			lastNudge = time.Now()
+		}
+
+		_ = s.conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := s.conn.Read(buf)
+		if n > 0 {
+			s.out.Write(s.filter(buf[:n]))
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			return s.out.String(), fmt.Errorf("console read failed: %s", err)
+		}
+	}
+}
+
+// consoleDrainQuiet is how long the console must stay silent for drain to stop.
+const consoleDrainQuiet = 500 * time.Millisecond
+
+// drain reads until the console has been quiet for consoleDrainQuiet, or until
+// timeout, and discards everything read so far. After nudging for a prompt it drops
+// the extra prompts a slow device printed for the other empty lines, so they are not
+// taken for the end of the next command's output.
+func (s *consoleSession) drain(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, 4096)
+	for time.Now().Before(deadline) {
+		_ = s.conn.SetReadDeadline(time.Now().Add(consoleDrainQuiet))
+		n, err := s.conn.Read(buf)
+		if n > 0 {
+			s.out.Write(s.filter(buf[:n]))
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				break
+			}
+			return fmt.Errorf("console read failed: %s", err)
+		}
+	}
+	s.out.Reset()
+	return nil
+}
+
+// waitForSchema returns the optional console readiness block shared by node resources.
+func waitForSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Wait after start until the node's telnet console prints a pattern (e.g. login: or Router>).",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"console_pattern": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsValidRegExp,
+					Description:  "Regular expression expected on the console.",
+				},
+				"timeout_seconds": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      300,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Maximum seconds to wait for the pattern.",
+				},
+			},
+		},
+	}
+}
+
+// waitForCustomizeDiff fails the plan when wait_for is set on a node that is not
+// going to be started, since there is no console to wait for. legacyStartKey is the
+// deprecated boolean start flag of the resource, if it has one.
+func waitForCustomizeDiff(legacyStartKey string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		raw := d.GetRawConfig()
+		waitFor := raw.GetAttr("wait_for")
+		if !waitFor.IsKnown() || waitFor.IsNull() || waitFor.LengthInt() == 0 {
+			return nil
+		}
+
+		status := raw.GetAttr("status")
+		if !status.IsKnown() {
+			return nil
+		}
+		if !status.IsNull() {
+			if s := status.AsString(); s != "started" {
+				return fmt.Errorf("wait_for requires status = \"started\", got %q", s)
+			}
+			return nil
+		}
+		if legacyStartKey != "" {
+			start := raw.GetAttr(legacyStartKey)
+			if !start.IsKnown() || (!start.IsNull() && start.True()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("wait_for requires status = \"started\"")
+	}
+}
+
+// waitForNodeConsole honours a resource's wait_for block once the node has been started.
+func waitForNodeConsole(d *schema.ResourceData, host, projectID, nodeID string) error {
+	blocks := d.Get("wait_for").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	waitFor := blocks[0].(map[string]interface{})
+	pattern := regexp.MustCompile(waitFor["console_pattern"].(string))
+	timeout := time.Duration(waitFor["timeout_seconds"].(int)) * time.Second
+	deadline := time.Now().Add(timeout)
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("node %s not found while waiting for console", nodeID)
+	}
+	if s, _ := node["status"].(string); s != "started" {
+		return fmt.Errorf("wait_for requires node %s to be started, status is %q", nodeID, s)
+	}
+
+	session, err := openNodeConsole(host, node, timeout)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	log.Printf("[INFO] Waiting up to %s for console pattern %q on node %s", timeout, pattern.String(), nodeID)
+	if _, err := session.expect(pattern, time.Until(deadline), true); err != nil {
+		return fmt.Errorf("node %s not ready: %s", nodeID, err)
+	}
+	return nil
+}
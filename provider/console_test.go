@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// telnetStandIn accepts one connection, writes script to it and records what the
+// client sends back until the connection is closed.
+func telnetStandIn(t *testing.T, script []byte) (string, <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %s", err)
+	}
+	received := make(chan []byte, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		if _, err := conn.Write(script); err != nil {
+			received <- nil
+			return
+		}
+		var got bytes.Buffer
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _ = io.Copy(&got, conn)
+		received <- got.Bytes()
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestConsoleSessionFiltersNegotiation(t *testing.T) {
+	script := []byte{
+		telnetIAC, telnetWILL, 1, // WILL ECHO
+		telnetIAC, telnetDO, 24, // DO TERMINAL-TYPE
+		telnetIAC, telnetSB, 24, 1, telnetIAC, telnetSE, // subnegotiation
+	}
+	script = append(script, []byte("Welcome\r\n")...)
+	script = append(script, telnetIAC, telnetIAC) // escaped 0xff data byte
+	script = append(script, []byte("\r\nRouter>")...)
+
+	addr, received := telnetStandIn(t, script)
+	session, err := dialConsole(addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := session.expect(regexp.MustCompile(`Router>`), 5*time.Second, false)
+	if err != nil {
+		t.Fatalf("expect: %s", err)
+	}
+	session.Close()
+
+	want := "Welcome\r\n\xff\r\nRouter>"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	replies := <-received
+	wantReplies := []byte{telnetIAC, telnetDONT, 1, telnetIAC, telnetWONT, 24}
+	if !bytes.Equal(replies, wantReplies) {
+		t.Errorf("negotiation replies = %v, want %v", replies, wantReplies)
+	}
+}
+
+func TestConsoleSessionKeepsOutputAfterMatch(t *testing.T) {
+	addr, _ := telnetStandIn(t, []byte("login: admin\r\nPassword: "))
+	session, err := dialConsole(addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	out, err := session.expect(regexp.MustCompile(`login: `), 5*time.Second, false)
+	if err != nil {
+		t.Fatalf("expect login: %s", err)
+	}
+	if out != "login: " {
+		t.Errorf("first match = %q, want %q", out, "login: ")
+	}
+	out, err = session.expect(regexp.MustCompile(`Password: `), 5*time.Second, false)
+	if err != nil {
+		t.Fatalf("expect password: %s", err)
+	}
+	if out != "admin\r\nPassword: " {
+		t.Errorf("second match = %q, want %q", out, "admin\r\nPassword: ")
+	}
+}
+
+func TestConsoleSessionTimeout(t *testing.T) {
+	addr, _ := telnetStandIn(t, []byte("booting...\r\n"))
+	session, err := dialConsole(addr, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	start := time.Now()
+	out, err := session.expect(regexp.MustCompile(`Router>`), 1500*time.Millisecond, false)
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("error = %q, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expect took %s, should stop near its timeout", elapsed)
+	}
+	if out != "booting...\r\n" {
+		t.Errorf("output = %q, want the text read so far", out)
+	}
+}
+
+func TestConsoleSessionDrainDropsExtraPrompts(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %s", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// A slow device answering two nudges prints two prompts
+		_, _ = conn.Write([]byte("\r\nRouter#\r\nRouter#"))
+		line := make([]byte, 64)
+		if _, err := conn.Read(line); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("show clock\r\n10:00:00 UTC\r\nRouter#"))
+	}()
+
+	session, err := dialConsole(ln.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	prompt := regexp.MustCompile(defaultConsolePrompt)
+	if _, err := session.expect(prompt, 5*time.Second, false); err != nil {
+		t.Fatalf("expect prompt: %s", err)
+	}
+	if err := session.drain(5 * time.Second); err != nil {
+		t.Fatalf("drain: %s", err)
+	}
+	if err := session.send("show clock"); err != nil {
+		t.Fatalf("send: %s", err)
+	}
+	out, err := session.expect(prompt, 5*time.Second, false)
+	if err != nil {
+		t.Fatalf("expect output: %s", err)
+	}
+	if !strings.Contains(out, "10:00:00 UTC") {
+		t.Errorf("command output = %q, want the clock, not the stale prompt", out)
+	}
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceGns3Computes lists the computes registered on the controller
+func dataSourceGns3Computes() *schema.Resource {
+	compute := computeStatusSchema()
+	compute["compute_id"] = &schema.Schema{Type: schema.TypeString, Computed: true}
+	compute["name"] = &schema.Schema{Type: schema.TypeString, Computed: true}
+	compute["protocol"] = &schema.Schema{Type: schema.TypeString, Computed: true}
+	compute["host"] = &schema.Schema{Type: schema.TypeString, Computed: true}
+	compute["port"] = &schema.Schema{Type: schema.TypeInt, Computed: true}
+
+	return &schema.Resource{
+		Read: dataSourceGns3ComputesRead,
+		Schema: map[string]*schema.Schema{
+			"computes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Computes with their usage and capabilities",
+				Elem:        &schema.Resource{Schema: compute},
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the computes",
+			},
+		},
+	}
+}
+
+func dataSourceGns3ComputesRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	listed, err := listComputes(config.Host)
+	if err != nil {
+		return err
+	}
+
+	computes := make([]interface{}, 0, len(listed))
+	ids := make([]string, 0, len(listed))
+	for _, compute := range listed {
+		id, _ := compute["compute_id"].(string)
+		name, _ := compute["name"].(string)
+		protocol, _ := compute["protocol"].(string)
+		host, _ := compute["host"].(string)
+		port, _ := compute["port"].(float64)
+
+		entry := flattenComputeStatus(compute)
+		entry["compute_id"] = id
+		entry["name"] = name
+		entry["protocol"] = protocol
+		entry["host"] = host
+		entry["port"] = int(port)
+		computes = append(computes, entry)
+		ids = append(ids, id)
+	}
+
+	d.SetId("computes")
+	if err := d.Set("computes", computes); err != nil {
+		return fmt.Errorf("failed to set computes: %s", err)
+	}
+	if err := d.Set("ids", ids); err != nil {
+		return fmt.Errorf("failed to set ids: %s", err)
+	}
+	return nil
+}
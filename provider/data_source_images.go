@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceGns3Images lists the images a compute holds for one emulator
+func dataSourceGns3Images() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGns3ImagesRead,
+		Schema: map[string]*schema.Schema{
+			"compute_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "local",
+				Description: "Compute to list images on",
+			},
+			"image_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(imageTypes, false),
+				Description:  "Emulator to list images for: qemu, iou or dynamips",
+			},
+			"images": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Images available on the compute",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"filename": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"md5sum": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"filesize": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"filenames": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Filenames of the available images",
+			},
+		},
+	}
+}
+
+func dataSourceGns3ImagesRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	computeID := d.Get("compute_id").(string)
+	imageType := d.Get("image_type").(string)
+
+	listed, err := listImages(config.Host, computeID, imageType)
+	if err != nil {
+		return err
+	}
+
+	images := make([]interface{}, 0, len(listed))
+	filenames := make([]string, 0, len(listed))
+	for _, image := range listed {
+		filename, _ := image["filename"].(string)
+		path, _ := image["path"].(string)
+		md5sum, _ := image["md5sum"].(string)
+		size, _ := image["filesize"].(float64)
+		images = append(images, map[string]interface{}{
+			"filename": filename,
+			"path":     path,
+			"md5sum":   md5sum,
+			"filesize": int(size),
+		})
+		filenames = append(filenames, filename)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", computeID, imageType))
+	if err := d.Set("images", images); err != nil {
+		return fmt.Errorf("failed to set images: %s", err)
+	}
+	if err := d.Set("filenames", filenames); err != nil {
+		return fmt.Errorf("failed to set filenames: %s", err)
+	}
+	return nil
+}
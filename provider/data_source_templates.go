@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// templateSummarySchema describes the common fields exported for each template.
+func templateSummarySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template_id":   {Type: schema.TypeString, Computed: true},
+		"name":          {Type: schema.TypeString, Computed: true},
+		"template_type": {Type: schema.TypeString, Computed: true},
+		"category":      {Type: schema.TypeString, Computed: true},
+		"compute_id":    {Type: schema.TypeString, Computed: true},
+		"builtin":       {Type: schema.TypeBool, Computed: true},
+		"symbol":        {Type: schema.TypeString, Computed: true},
+		"image":         {Type: schema.TypeString, Computed: true},
+		"ram":           {Type: schema.TypeInt, Computed: true},
+		"adapters":      {Type: schema.TypeInt, Computed: true},
+	}
+}
+
+// templateSummary extracts the common fields and key properties of a template.
+func templateSummary(template map[string]interface{}) map[string]interface{} {
+	templateType, _ := template["template_type"].(string)
+	summary := map[string]interface{}{
+		"template_id":   template["template_id"],
+		"name":          template["name"],
+		"template_type": templateType,
+		"category":      template["category"],
+		"compute_id":    template["compute_id"],
+		"builtin":       template["builtin"],
+		"symbol":        template["symbol"],
+		"image":         "",
+		"ram":           0,
+		"adapters":      0,
+	}
+	if key, ok := templateImageKeys[templateType]; ok {
+		summary["image"] = template[key]
+	}
+	if v, ok := template["ram"].(float64); ok {
+		summary["ram"] = int(v)
+	}
+	if key, ok := templateAdapterKeys[templateType]; ok {
+		if v, ok := template[key].(float64); ok {
+			summary["adapters"] = int(v)
+		}
+	}
+	// The controller may return null for unset fields
+	for key, value := range summary {
+		if value == nil {
+			delete(summary, key)
+		}
+	}
+	return summary
+}
+
+// dataSourceGns3Templates lists templates, optionally filtered by type, category and name
+func dataSourceGns3Templates() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGns3TemplatesRead,
+		Schema: map[string]*schema.Schema{
+			"template_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return templates of this type, e.g. qemu",
+			},
+			"category": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return templates in this category, e.g. router",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return templates whose name matches this regular expression",
+			},
+			"templates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching templates, sorted as returned by GNS3",
+				Elem:        &schema.Resource{Schema: templateSummarySchema()},
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the matching templates",
+			},
+		},
+	}
+}
+
+func dataSourceGns3TemplatesRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	templateType := d.Get("template_type").(string)
+	category := d.Get("category").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	templates, err := listTemplates(config.Host)
+	if err != nil {
+		return fmt.Errorf("error fetching templates from GNS3 server: %s", err)
+	}
+
+	matched := make([]interface{}, 0, len(templates))
+	ids := make([]string, 0, len(templates))
+	for _, template := range templates {
+		name, _ := template["name"].(string)
+		if t, _ := template["template_type"].(string); templateType != "" && t != templateType {
+			continue
+		}
+		if c, _ := template["category"].(string); category != "" && c != category {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(name) {
+			continue
+		}
+		id, _ := template["template_id"].(string)
+		matched = append(matched, templateSummary(template))
+		ids = append(ids, id)
+	}
+
+	d.SetId(strings.Join([]string{"templates", templateType, category, d.Get("name_regex").(string)}, "/"))
+	if err := d.Set("templates", matched); err != nil {
+		return fmt.Errorf("failed to set templates: %s", err)
+	}
+	if err := d.Set("ids", ids); err != nil {
+		return fmt.Errorf("failed to set ids: %s", err)
+	}
+	return nil
+}
+
+// dataSourceGns3TemplateDetails exposes a single template with all of its settings
+func dataSourceGns3TemplateDetails() *schema.Resource {
+	s := templateSummarySchema()
+	s["template_id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"template_id", "name"},
+		Description:  "ID of the template",
+	}
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		Description: "Name of the template",
+	}
+	s["settings"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "All template settings as a JSON object",
+	}
+	s["properties"] = &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "All template settings as strings; lists and objects are JSON encoded",
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGns3TemplateDetailsRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGns3TemplateDetailsRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	templateID := d.Get("template_id").(string)
+	templateName := d.Get("name").(string)
+
+	templates, err := listTemplates(config.Host)
+	if err != nil {
+		return fmt.Errorf("error fetching templates from GNS3 server: %s", err)
+	}
+
+	var found map[string]interface{}
+	for _, template := range templates {
+		id, _ := template["template_id"].(string)
+		name, _ := template["name"].(string)
+		if (templateID != "" && id == templateID) || (templateID == "" && name == templateName) {
+			// GNS3 allows several templates with the same name
+			if found != nil {
+				return fmt.Errorf("more than one template is named '%s'; set template_id instead", templateName)
+			}
+			found = template
+		}
+	}
+	if found == nil {
+		if templateID != "" {
+			return fmt.Errorf("template with ID '%s' not found", templateID)
+		}
+		return fmt.Errorf("template with name '%s' not found", templateName)
+	}
+
+	for key, value := range templateSummary(found) {
+		d.Set(key, value)
+	}
+
+	settings, err := json.Marshal(found)
+	if err != nil {
+		return fmt.Errorf("failed to encode template settings: %s", err)
+	}
+	d.Set("settings", string(settings))
+
+	properties := make(map[string]string, len(found))
+	for key, value := range found {
+		properties[key] = formatPropertyValue(value)
+	}
+	if err := d.Set("properties", properties); err != nil {
+		return fmt.Errorf("failed to set properties: %s", err)
+	}
+
+	id, _ := found["template_id"].(string)
+	d.SetId(id)
+	return nil
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceGns3VirtualBoxVMs lists the VirtualBox VMs a compute exposes
+func dataSourceGns3VirtualBoxVMs() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceGns3VirtualBoxVMsRead,
+		Schema: vmListSchema(),
+	}
+}
+
+// dataSourceGns3VMwareVMs lists the VMware VMs a compute exposes
+func dataSourceGns3VMwareVMs() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceGns3VMwareVMsRead,
+		Schema: vmListSchema(),
+	}
+}
+
+// vmListSchema is shared by the VirtualBox and VMware VM listings.
+func vmListSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"compute_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "local",
+			Description: "Compute to list VMs from",
+		},
+		"vms": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "VMs available on the compute",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"vmname": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"vmx_path": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"vmnames": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Names of the VMs available on the compute",
+		},
+	}
+}
+
+func dataSourceGns3VirtualBoxVMsRead(d *schema.ResourceData, meta interface{}) error {
+	return readComputeVMs(d, meta, "virtualbox")
+}
+
+func dataSourceGns3VMwareVMsRead(d *schema.ResourceData, meta interface{}) error {
+	return readComputeVMs(d, meta, "vmware")
+}
+
+// readComputeVMs fetches /v2/computes/{compute_id}/{emulator}/vms into the data source.
+func readComputeVMs(d *schema.ResourceData, meta interface{}, emulator string) error {
+	config := meta.(*ProviderConfig)
+	computeID := d.Get("compute_id").(string)
+
+	url := fmt.Sprintf("%s/v2/computes/%s/%s/vms", config.Host, computeID, emulator)
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s VMs: %s", emulator, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("GNS3 API returned %d when listing %s VMs: %s", resp.StatusCode, emulator, string(body))
+	}
+
+	var listed []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		return fmt.Errorf("failed to decode response: %s", err)
+	}
+
+	vms := make([]interface{}, 0, len(listed))
+	names := make([]string, 0, len(listed))
+	for _, vm := range listed {
+		name, _ := vm["vmname"].(string)
+		vmxPath, _ := vm["vmx_path"].(string)
+		vms = append(vms, map[string]interface{}{
+			"vmname":   name,
+			"vmx_path": vmxPath,
+		})
+		names = append(names, name)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s", computeID, emulator))
+	if err := d.Set("vms", vms); err != nil {
+		return fmt.Errorf("failed to set vms: %s", err)
+	}
+	if err := d.Set("vmnames", names); err != nil {
+		return fmt.Errorf("failed to set vmnames: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// isoSectorSize is the logical block size used for every generated image.
+const isoSectorSize = 2048
+
+// isoTimestamp is fixed so identical content always produces an identical image,
+// which keeps checksums stable across runs.
+var isoTimestamp = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// isoFile is a file placed in the root directory of a generated image.
+type isoFile struct {
+	name    string
+	content []byte
+}
+
+// buildISO writes a single-directory ISO9660 image with Joliet extensions, so the
+// original (lowercase, hyphenated) file names are visible to Linux guests.
+//
+// Layout: 16 system sectors, the primary, Joliet and terminator descriptors, the
+// four path tables, the two root directories and then the file data.
+func buildISO(volumeID string, files map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]isoFile, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, isoFile{name: name, content: files[name]})
+	}
+
+	const (
+		pvdSector        = 16
+		svdSector        = 17
+		terminatorSector = 18
+		pathLSector      = 19
+		pathMSector      = 20
+		jolietPathL      = 21
+		jolietPathM      = 22
+		rootSector       = 23
+		jolietRootSector = 24
+		firstDataSector  = 25
+	)
+
+	// Assign file extents
+	extents := make([]uint32, len(entries))
+	next := uint32(firstDataSector)
+	for i, entry := range entries {
+		extents[i] = next
+		next += sectorsFor(len(entry.content))
+	}
+	totalSectors := next
+
+	primaryNames, err := isoPrimaryNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	rootDir, err := isoDirectory(rootSector, entries, extents, primaryNames)
+	if err != nil {
+		return nil, err
+	}
+	jolietNames := make([][]byte, len(names))
+	for i, name := range names {
+		jolietNames[i] = ucs2(name)
+	}
+	jolietDir, err := isoDirectory(jolietRootSector, entries, extents, jolietNames)
+	if err != nil {
+		return nil, err
+	}
+
+	image := make([]byte, int(totalSectors)*isoSectorSize)
+	sector := func(n int) []byte {
+		return image[n*isoSectorSize : (n+1)*isoSectorSize]
+	}
+
+	copy(sector(pvdSector), isoVolumeDescriptor(1, volumeID, totalSectors, pathLSector, pathMSector, rootSector))
+	copy(sector(svdSector), isoVolumeDescriptor(2, volumeID, totalSectors, jolietPathL, jolietPathM, jolietRootSector))
+
+	terminator := sector(terminatorSector)
+	terminator[0] = 255
+	copy(terminator[1:6], "CD001")
+	terminator[6] = 1
+
+	copy(sector(pathLSector), isoPathTable(rootSector, binary.LittleEndian))
+	copy(sector(pathMSector), isoPathTable(rootSector, binary.BigEndian))
+	copy(sector(jolietPathL), isoPathTable(jolietRootSector, binary.LittleEndian))
+	copy(sector(jolietPathM), isoPathTable(jolietRootSector, binary.BigEndian))
+
+	copy(sector(rootSector), rootDir)
+	copy(sector(jolietRootSector), jolietDir)
+
+	for i, entry := range entries {
+		copy(image[int(extents[i])*isoSectorSize:], entry.content)
+	}
+
+	return image, nil
+}
+
+// sectorsFor returns how many sectors n bytes occupy (at least one).
+func sectorsFor(n int) uint32 {
+	if n == 0 {
+		return 1
+	}
+	return uint32((n + isoSectorSize - 1) / isoSectorSize)
+}
+
+// isoPrimaryNames maps file names to ISO9660 level 1 names such as USER_DAT.;1.
+func isoPrimaryNames(names []string) ([][]byte, error) {
+	mapped := make([][]byte, len(names))
+	seen := map[string]string{}
+	for i, name := range names {
+		base, ext := name, ""
+		if dot := strings.LastIndex(name, "."); dot > 0 {
+			base, ext = name[:dot], name[dot+1:]
+		}
+		base = isoDChars(base, 8)
+		ext = isoDChars(ext, 3)
+		id := base + "." + ext + ";1"
+		if other, ok := seen[id]; ok {
+			return nil, fmt.Errorf("file names %q and %q map to the same ISO9660 name %s", other, name, id)
+		}
+		seen[id] = name
+		mapped[i] = []byte(id)
+	}
+	return mapped, nil
+}
+
+// isoDChars upper-cases s, replaces characters outside A-Z, 0-9 and _ and truncates it.
+func isoDChars(s string, max int) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if b.Len() == max {
+			break
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// ucs2 encodes s as big-endian UCS-2, as Joliet requires.
+func ucs2(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	out := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.BigEndian.PutUint16(out[2*i:], c)
+	}
+	return out
+}
+
+// isoDirectory builds a root directory extent holding ".", ".." and the files.
+func isoDirectory(self uint32, entries []isoFile, extents []uint32, names [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(isoDirRecord(self, isoSectorSize, true, []byte{0}))
+	buf.Write(isoDirRecord(self, isoSectorSize, true, []byte{1}))
+	for i, entry := range entries {
+		buf.Write(isoDirRecord(extents[i], uint32(len(entry.content)), false, names[i]))
+	}
+	if buf.Len() > isoSectorSize {
+		return nil, fmt.Errorf("too many files for a single-sector ISO directory")
+	}
+	return buf.Bytes(), nil
+}
+
+// isoDirRecord encodes one directory record.
+func isoDirRecord(extent, size uint32, dir bool, name []byte) []byte {
+	length := 33 + len(name)
+	if length%2 == 1 {
+		length++
+	}
+	rec := make([]byte, length)
+	rec[0] = byte(length)
+	putBothEndian32(rec[2:], extent)
+	putBothEndian32(rec[10:], size)
+	copy(rec[18:25], isoRecordingDate(isoTimestamp))
+	if dir {
+		rec[25] = 2
+	}
+	putBothEndian16(rec[28:], 1)
+	rec[32] = byte(len(name))
+	copy(rec[33:], name)
+	return rec
+}
+
+// isoPathTable encodes a path table holding only the root directory.
+func isoPathTable(rootExtent uint32, order binary.ByteOrder) []byte {
+	table := make([]byte, 10)
+	table[0] = 1
+	order.PutUint32(table[2:], rootExtent)
+	order.PutUint16(table[6:], 1)
+	return table
+}
+
+// isoVolumeDescriptor encodes the primary (type 1) or Joliet supplementary (type 2) descriptor.
+func isoVolumeDescriptor(kind byte, volumeID string, totalSectors, pathL, pathM, rootExtent uint32) []byte {
+	vd := make([]byte, isoSectorSize)
+	vd[0] = kind
+	copy(vd[1:6], "CD001")
+	vd[6] = 1
+
+	joliet := kind == 2
+	text := func(field []byte, s string) {
+		if joliet {
+			for i := 0; i+1 < len(field); i += 2 {
+				field[i], field[i+1] = 0, ' '
+			}
+			copy(field, ucs2(s))
+			return
+		}
+		for i := range field {
+			field[i] = ' '
+		}
+		copy(field, s)
+	}
+
+	label := strings.ToUpper(volumeID)
+	if joliet {
+		label = volumeID
+		copy(vd[88:91], "%/E") // UCS-2 level 3
+	}
+	text(vd[8:40], "")
+	text(vd[40:72], label)
+	putBothEndian32(vd[80:], totalSectors)
+	putBothEndian16(vd[120:], 1)
+	putBothEndian16(vd[124:], 1)
+	putBothEndian16(vd[128:], isoSectorSize)
+	putBothEndian32(vd[132:], 10)
+	binary.LittleEndian.PutUint32(vd[140:], pathL)
+	binary.BigEndian.PutUint32(vd[148:], pathM)
+	copy(vd[156:190], isoDirRecord(rootExtent, isoSectorSize, true, []byte{0}))
+	text(vd[190:318], "")
+	text(vd[318:446], "")
+	text(vd[446:574], "")
+	text(vd[574:702], "TERRAFORM-PROVIDER-GNS3")
+	text(vd[702:739], "")
+	text(vd[739:776], "")
+	text(vd[776:813], "")
+
+	created := isoVolumeDate(isoTimestamp)
+	copy(vd[813:830], created)
+	copy(vd[830:847], created)
+	copy(vd[847:864], isoVolumeDate(time.Time{}))
+	copy(vd[864:881], created)
+	vd[881] = 1
+	return vd
+}
+
+// isoRecordingDate encodes the 7-byte directory record timestamp.
+func isoRecordingDate(t time.Time) []byte {
+	return []byte{
+		byte(t.Year() - 1900), byte(t.Month()), byte(t.Day()),
+		byte(t.Hour()), byte(t.Minute()), byte(t.Second()), 0,
+	}
+}
+
+// isoVolumeDate encodes the 17-byte volume descriptor timestamp; the zero time means "not specified".
+func isoVolumeDate(t time.Time) []byte {
+	if t.IsZero() {
+		return append([]byte("0000000000000000"), 0)
+	}
+	return append([]byte(t.Format("20060102150405")+"00"), 0)
+}
+
+func putBothEndian16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b, v)
+	binary.BigEndian.PutUint16(b[2:], v)
+}
+
+func putBothEndian32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b, v)
+	binary.BigEndian.PutUint32(b[4:], v)
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// isoEntry is a file read back from a generated image.
+type isoEntry struct {
+	extent  uint32
+	content []byte
+}
+
+// readISODirectory reads the root directory that the volume descriptor in sector n
+// points to, decoding names as UCS-2 when joliet is set.
+func readISODirectory(t *testing.T, image []byte, n int, joliet bool) map[string]isoEntry {
+	t.Helper()
+	vd := image[n*isoSectorSize : (n+1)*isoSectorSize]
+	root := vd[156:190]
+	extent := binary.LittleEndian.Uint32(root[2:])
+	size := binary.LittleEndian.Uint32(root[10:])
+	if binary.BigEndian.Uint32(root[6:]) != extent || binary.BigEndian.Uint32(root[14:]) != size {
+		t.Fatalf("descriptor %d: root record endians disagree", n)
+	}
+
+	dir := image[int(extent)*isoSectorSize : int(extent)*isoSectorSize+int(size)]
+	entries := map[string]isoEntry{}
+	for off := 0; off < len(dir) && dir[off] != 0; off += int(dir[off]) {
+		rec := dir[off : off+int(dir[off])]
+		name := rec[33 : 33+int(rec[32])]
+		if len(name) == 1 && (name[0] == 0 || name[0] == 1) {
+			continue
+		}
+		if rec[25]&2 != 0 {
+			t.Fatalf("descriptor %d: unexpected subdirectory %q", n, name)
+		}
+		fileExtent := binary.LittleEndian.Uint32(rec[2:])
+		fileSize := binary.LittleEndian.Uint32(rec[10:])
+		start := int(fileExtent) * isoSectorSize
+
+		decoded := string(name)
+		if joliet {
+			units := make([]uint16, len(name)/2)
+			for i := range units {
+				units[i] = binary.BigEndian.Uint16(name[2*i:])
+			}
+			decoded = string(utf16.Decode(units))
+		}
+		entries[decoded] = isoEntry{extent: fileExtent, content: image[start : start+int(fileSize)]}
+	}
+	return entries
+}
+
+func TestBuildISORoundTrip(t *testing.T) {
+	files := map[string][]byte{
+		"user-data":      []byte("#cloud-config\n" + strings.Repeat("# padding\n", 500)),
+		"meta-data":      []byte("instance-id: r1\n"),
+		"network-config": {},
+	}
+	image, err := buildISO("cidata", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(image)%isoSectorSize != 0 {
+		t.Fatalf("image size %d is not a whole number of sectors", len(image))
+	}
+
+	for n, kind := range map[int]byte{16: 1, 17: 2, 18: 255} {
+		vd := image[n*isoSectorSize:]
+		if vd[0] != kind || string(vd[1:6]) != "CD001" {
+			t.Errorf("sector %d: descriptor type %d %q, want %d CD001", n, vd[0], vd[1:6], kind)
+		}
+	}
+	pvd := image[16*isoSectorSize:]
+	if sectors := binary.LittleEndian.Uint32(pvd[80:]); int(sectors)*isoSectorSize != len(image) {
+		t.Errorf("volume space size %d sectors, image has %d", sectors, len(image)/isoSectorSize)
+	}
+	if label := strings.TrimRight(string(pvd[40:72]), " "); label != "CIDATA" {
+		t.Errorf("primary volume ID = %q, want CIDATA", label)
+	}
+	svd := image[17*isoSectorSize:]
+	if string(svd[88:91]) != "%/E" {
+		t.Errorf("Joliet escape sequence = %q, want %%/E", svd[88:91])
+	}
+	if !bytes.HasPrefix(svd[40:72], ucs2("cidata")) {
+		t.Errorf("Joliet volume ID does not start with cidata")
+	}
+
+	joliet := readISODirectory(t, image, 17, true)
+	if len(joliet) != len(files) {
+		t.Errorf("Joliet directory has %d files, want %d", len(joliet), len(files))
+	}
+	for name, content := range files {
+		entry, ok := joliet[name]
+		if !ok {
+			t.Errorf("Joliet directory is missing %s", name)
+			continue
+		}
+		if !bytes.Equal(entry.content, content) {
+			t.Errorf("%s: read back %d bytes, want %d", name, len(entry.content), len(content))
+		}
+	}
+
+	primary := readISODirectory(t, image, 16, false)
+	for name, want := range map[string]string{
+		"META_DAT.;1": "meta-data",
+		"NETWORK_.;1": "network-config",
+		"USER_DAT.;1": "user-data",
+	} {
+		entry, ok := primary[name]
+		if !ok {
+			t.Errorf("primary directory is missing %s", name)
+			continue
+		}
+		if entry.extent != joliet[want].extent || !bytes.Equal(entry.content, files[want]) {
+			t.Errorf("primary %s does not point at the data of %s", name, want)
+		}
+	}
+}
+
+func TestBuildISODeterministic(t *testing.T) {
+	files := map[string][]byte{"user-data": []byte("#cloud-config\n"), "meta-data": []byte("instance-id: a\n")}
+	first, err := buildISO("cidata", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := buildISO("cidata", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("the same files produced different images")
+	}
+}
+
+func TestBuildISONameCollision(t *testing.T) {
+	_, err := buildISO("cidata", map[string][]byte{"user-data": nil, "user_data": nil})
+	if err == nil || !strings.Contains(err.Error(), "same ISO9660 name") {
+		t.Errorf("error = %v, want a name collision", err)
+	}
+}
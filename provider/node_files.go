@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// uploadNodeFile writes content to a file relative to the node's working directory.
+func uploadNodeFile(host, projectID, nodeID, path, content string) error {
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/files/%s", host, projectID, nodeID, path)
+	resp, err := http.Post(url, "application/octet-stream", bytes.NewBufferString(content))
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to node %s: %s", path, nodeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to upload %s to node %s, status: %d, response: %s", path, nodeID, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// readNodeFile downloads a file from the node's working directory. found is false
+// when the file does not exist.
+func readNodeFile(host, projectID, nodeID, path string) (string, bool, error) {
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/files/%s", host, projectID, nodeID, path)
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s from node %s: %s", path, nodeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("failed to read %s from node %s, status: %d, response: %s", path, nodeID, resp.StatusCode, string(body))
+	}
+	return string(body), true, nil
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// nodeConfigPath returns where a node type keeps its startup or private configuration.
+func nodeConfigPath(node map[string]interface{}, private bool) (string, error) {
+	nodeType, _ := node["node_type"].(string)
+	switch nodeType {
+	case "iou":
+		if private {
+			return "private-config.cfg", nil
+		}
+		return "startup-config.cfg", nil
+	case "dynamips":
+		props, _ := node["properties"].(map[string]interface{})
+		dynamipsID, ok := props["dynamips_id"].(float64)
+		if !ok {
+			return "", fmt.Errorf("dynamips node %v has no dynamips_id", node["name"])
+		}
+		if private {
+			return fmt.Sprintf("configs/i%d_private-config.cfg", int(dynamipsID)), nil
+		}
+		return fmt.Sprintf("configs/i%d_startup-config.cfg", int(dynamipsID)), nil
+	case "vpcs":
+		if !private {
+			return "startup.vpc", nil
+		}
+	}
+
+	kind := "startup"
+	if private {
+		kind = "private"
+	}
+	return "", fmt.Errorf("node type %q has no %s configuration file, use files instead", nodeType, kind)
+}
+
+// nodeFilesSchema returns the generic file map attribute shared by node resources.
+func nodeFilesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Description: "Files to upload to the node before it starts, keyed by path relative to the node directory.",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// nodeConfigSchema returns a startup/private configuration content attribute.
+func nodeConfigSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: description,
+	}
+}
+
+// reloadOnChangeSchema returns the flag that reloads a running node after a file changes.
+func reloadOnChangeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Reload the node when an uploaded file changes while it is running.",
+	}
+}
+
+// fileHashesSchema returns the computed map of remote file hashes.
+func fileHashesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "SHA-256 of each managed file as read back from the node.",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// desiredNodeFiles collects the files a resource manages, keyed by node path. The
+// attribute each path came from is returned alongside so read-back can update it.
+func desiredNodeFiles(d *schema.ResourceData, node map[string]interface{}) (map[string]string, map[string]string, error) {
+	files := map[string]string{}
+	sources := map[string]string{}
+
+	for _, attr := range []string{"startup_config", "private_config"} {
+		if _, ok := d.GetOk(attr); !ok {
+			continue
+		}
+		path, err := nodeConfigPath(node, attr == "private_config")
+		if err != nil {
+			return nil, nil, err
+		}
+		files[path] = d.Get(attr).(string)
+		sources[path] = attr
+	}
+
+	if v, ok := d.GetOk("files"); ok {
+		for path, content := range v.(map[string]interface{}) {
+			if _, dup := files[path]; dup {
+				return nil, nil, fmt.Errorf("file %s is set by both files and %s", path, sources[path])
+			}
+			files[path] = content.(string)
+			sources[path] = "files"
+		}
+	}
+	return files, sources, nil
+}
+
+// uploadNodeFiles pushes every managed file whose content differs from the node copy
+// and, when requested, reloads a running node so it picks up the change.
+func uploadNodeFiles(d *schema.ResourceData, host, projectID, nodeID string) error {
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("node %s not found while uploading files", nodeID)
+	}
+
+	files, _, err := desiredNodeFiles(d, node)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for path, content := range files {
+		remote, exists, err := readNodeFile(host, projectID, nodeID, path)
+		if err != nil {
+			return err
+		}
+		if exists && remote == content {
+			continue
+		}
+		if err := uploadNodeFile(host, projectID, nodeID, path, content); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if changed && d.Get("reload_on_change").(bool) {
+		if s, _ := node["status"].(string); s == "started" {
+			return nodeAction(host, projectID, nodeID, "reload")
+		}
+	}
+	return nil
+}
+
+// readNodeFiles reads managed files back from the node, records their hashes and
+// replaces drifted content in state so the next plan re-uploads it.
+func readNodeFiles(d *schema.ResourceData, host, projectID, nodeID string, node map[string]interface{}) error {
+	files, sources, err := desiredNodeFiles(d, node)
+	if err != nil {
+		return err
+	}
+
+	hashes := map[string]interface{}{}
+	remoteFiles := map[string]interface{}{}
+	for path, content := range files {
+		remote, exists, err := readNodeFile(host, projectID, nodeID, path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// Dropping the file from state makes Terraform upload it again
+			if sources[path] != "files" {
+				d.Set(sources[path], "")
+			}
+			continue
+		}
+
+		hashes[path] = contentHash(remote)
+		if contentHash(content) != hashes[path] && sources[path] != "files" {
+			d.Set(sources[path], remote)
+		}
+		if sources[path] == "files" {
+			remoteFiles[path] = remote
+		}
+	}
+
+	if _, ok := d.GetOk("files"); ok {
+		d.Set("files", remoteFiles)
+	}
+	return d.Set("file_hashes", hashes)
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// placementPolicies are the accepted values of the provider's placement_policy.
+var placementPolicies = []string{"least_loaded", "round_robin"}
+
+// computePlacement tracks the choices made during one run, so nodes created in
+// parallel are spread before the compute statistics catch up with them.
+type computePlacement struct {
+	mu       sync.Mutex
+	reserved map[string]int64
+	next     int
+}
+
+// computeLoad is a compute that can take a node, with its free RAM in bytes.
+type computeLoad struct {
+	ID      string
+	FreeRAM int64
+	CPU     float64
+}
+
+// autoComputeLabel reports whether compute_id asks for automatic placement, and
+// the label that restricts the candidates for "auto:<label>".
+func autoComputeLabel(computeID string) (string, bool) {
+	if computeID == "auto" {
+		return "", true
+	}
+	if strings.HasPrefix(computeID, "auto:") {
+		return strings.TrimPrefix(computeID, "auto:"), true
+	}
+	return "", false
+}
+
+// placementSchema records the compute_id value a node was placed with.
+func placementSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The \"auto\" or \"auto:<label>\" value compute_id was resolved from; empty when compute_id was set explicitly.",
+	}
+}
+
+// suppressAutoComputeID keeps the compute picked for "auto" from showing up as drift.
+// Only nodes that were themselves placed are covered: "auto" accepts whatever compute
+// was picked, while "auto:<label>" must be the request the node was placed with, so
+// moving a node to a label or from an explicit compute to "auto" still replaces it.
+func suppressAutoComputeID(k, old, new string, d *schema.ResourceData) bool {
+	if _, auto := autoComputeLabel(new); !auto || old == "" {
+		return false
+	}
+	placed := d.Get("placement").(string)
+	if placed == "" {
+		return false
+	}
+	return new == "auto" || new == placed
+}
+
+// parseComputeLabels converts the provider's compute_labels into label lists.
+func parseComputeLabels(raw map[string]interface{}) map[string][]string {
+	labels := make(map[string][]string, len(raw))
+	for computeID, v := range raw {
+		for _, label := range strings.Split(v.(string), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels[computeID] = append(labels[computeID], label)
+			}
+		}
+	}
+	return labels
+}
+
+// placementCandidates returns the connected computes carrying the label, sorted by ID.
+func placementCandidates(config *ProviderConfig, label string) ([]computeLoad, error) {
+	computes, err := listComputes(config.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []computeLoad{}
+	for _, compute := range computes {
+		computeID, _ := compute["compute_id"].(string)
+		if connected, _ := compute["connected"].(bool); !connected {
+			continue
+		}
+		if label != "" && !containsString(config.ComputeLabels[computeID], label) {
+			continue
+		}
+		caps, _ := compute["capabilities"].(map[string]interface{})
+		memory, _ := caps["memory"].(float64)
+		memoryUsage, _ := compute["memory_usage_percent"].(float64)
+		cpuUsage, _ := compute["cpu_usage_percent"].(float64)
+		candidates = append(candidates, computeLoad{
+			ID:      computeID,
+			FreeRAM: int64(memory * (1 - memoryUsage/100)),
+			CPU:     cpuUsage,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	return candidates, nil
+}
+
+// fittingComputes keeps the candidates with at least ramMB of free RAM left.
+func fittingComputes(candidates []computeLoad, reserved map[string]int64, ramMB int) []computeLoad {
+	need := int64(ramMB) * 1024 * 1024
+	fitting := []computeLoad{}
+	for _, c := range candidates {
+		c.FreeRAM -= reserved[c.ID]
+		if c.FreeRAM >= need {
+			fitting = append(fitting, c)
+		}
+	}
+	return fitting
+}
+
+// noComputeError explains why automatic placement found no compute.
+func noComputeError(label string, ramMB int) error {
+	scope := "no connected compute"
+	if label != "" {
+		scope = fmt.Sprintf("no connected compute labelled %q", label)
+	}
+	return fmt.Errorf("automatic placement failed: %s has %d MB of free RAM for this node", scope, ramMB)
+}
+
+// placeComputeID resolves compute_id for a new node. "auto" and "auto:<label>" pick a
+// compute according to the provider's placement_policy; the choice is stored in
+// compute_id and the request in placement. Other values are returned unchanged.
+// ramKey names the node's RAM attribute in MB, or is empty for nodes without one.
+func placeComputeID(d *schema.ResourceData, meta interface{}, ramKey string) (string, error) {
+	ramMB := 0
+	if ramKey != "" {
+		ramMB = d.Get(ramKey).(int)
+	}
+	return placeComputeIDWithRAM(d, meta, ramMB)
+}
+
+// placeComputeIDWithRAM is placeComputeID for nodes whose RAM is not an attribute,
+// such as template nodes.
+func placeComputeIDWithRAM(d *schema.ResourceData, meta interface{}, ramMB int) (string, error) {
+	computeID := d.Get("compute_id").(string)
+	label, auto := autoComputeLabel(computeID)
+	if !auto {
+		return computeID, nil
+	}
+
+	config := meta.(*ProviderConfig)
+	candidates, err := placementCandidates(config, label)
+	if err != nil {
+		return "", err
+	}
+
+	placement := config.placement
+	placement.mu.Lock()
+	defer placement.mu.Unlock()
+
+	fitting := fittingComputes(candidates, placement.reserved, ramMB)
+	if len(fitting) == 0 {
+		return "", noComputeError(label, ramMB)
+	}
+
+	var chosen computeLoad
+	switch config.PlacementPolicy {
+	case "round_robin":
+		chosen = fitting[placement.next%len(fitting)]
+		placement.next++
+	default:
+		chosen = fitting[0]
+		for _, c := range fitting[1:] {
+			if c.FreeRAM > chosen.FreeRAM || (c.FreeRAM == chosen.FreeRAM && c.CPU < chosen.CPU) {
+				chosen = c
+			}
+		}
+	}
+	placement.reserved[chosen.ID] += int64(ramMB) * 1024 * 1024
+
+	d.Set("compute_id", chosen.ID)
+	d.Set("placement", computeID)
+	return chosen.ID, nil
+}
+
+// placementRAMFunc returns the RAM in MB a new node needs, with false when it is
+// not known at plan time.
+type placementRAMFunc func(d *schema.ResourceDiff, meta interface{}) (int, bool, error)
+
+// computePlacementCustomizeDiff fails the plan of a new node with compute_id "auto"
+// when no compute has enough free RAM for it. ramKey is as for placeComputeID.
+func computePlacementCustomizeDiff(ramKey string) schema.CustomizeDiffFunc {
+	return computePlacementCustomizeDiffWithRAM(func(d *schema.ResourceDiff, meta interface{}) (int, bool, error) {
+		if ramKey == "" {
+			return 0, true, nil
+		}
+		if !d.NewValueKnown(ramKey) {
+			return 0, false, nil
+		}
+		return d.Get(ramKey).(int), true, nil
+	})
+}
+
+// computePlacementCustomizeDiffWithRAM is computePlacementCustomizeDiff for nodes whose
+// RAM has to be looked up.
+func computePlacementCustomizeDiffWithRAM(ram placementRAMFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() != "" || !d.NewValueKnown("compute_id") {
+			return nil
+		}
+		label, auto := autoComputeLabel(d.Get("compute_id").(string))
+		if !auto {
+			return nil
+		}
+
+		ramMB, known, err := ram(d, meta)
+		if err != nil || !known {
+			return err
+		}
+
+		config := meta.(*ProviderConfig)
+		candidates, err := placementCandidates(config, label)
+		if err != nil {
+			return err
+		}
+		if len(fittingComputes(candidates, nil, ramMB)) == 0 {
+			return noComputeError(label, ramMB)
+		}
+		return nil
+	}
+}
@@ -5,12 +5,19 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // ProviderConfig holds configuration for the provider.
 type ProviderConfig struct {
-	Host   string
-	APIURL string
+	Host         string
+	APIURL       string
+	IOURCContent string
+
+	// PlacementPolicy and ComputeLabels drive compute_id = "auto".
+	PlacementPolicy string
+	ComputeLabels   map[string][]string
+	placement       *computePlacement
 }
 
 // Provider returns the Terraform provider for GNS3.
@@ -23,21 +30,64 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("GNS3_HOST", "http://localhost:3080"),
 				Description: "The GNS3 server host URL. Default: http://localhost:3080",
 			},
+			"iourc_content": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("GNS3_IOURC_CONTENT", ""),
+				Description: "Content of the iourc license file, pushed to the GNS3 IOU license settings before IOU nodes are created or updated.",
+			},
+			"placement_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "least_loaded",
+				ValidateFunc: validation.StringInSlice(placementPolicies, false),
+				Description:  "How nodes with compute_id = \"auto\" are placed: least_loaded (most free RAM, then lowest CPU) or round_robin.",
+			},
+			"compute_labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Comma-separated labels per compute ID. compute_id = \"auto:<label>\" only places nodes on computes with that label.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"gns3_project":   resourceGns3Project(),
-			"gns3_cloud":     resourceGns3Cloud(),
-			"gns3_switch":    resourceGns3Switch(),
-			"gns3_template":  resourceGns3Template(),
-			"gns3_link":      resourceGns3Link(),
-			"gns3_start_all": resourceGns3StartAll(),
-			"gns3_docker":    resourceGns3Docker(),
-			"gns3_qemu_node": resourceGns3Qemu(),
+			"gns3_project":             resourceGns3Project(),
+			"gns3_cloud":               resourceGns3Cloud(),
+			"gns3_switch":              resourceGns3Switch(),
+			"gns3_template":            resourceGns3Template(),
+			"gns3_link":                resourceGns3Link(),
+			"gns3_start_all":           resourceGns3StartAll(),
+			"gns3_docker":              resourceGns3Docker(),
+			"gns3_qemu_node":           resourceGns3Qemu(),
+			"gns3_boot_sequence":       resourceGns3BootSequence(),
+			"gns3_console_exec":        resourceGns3ConsoleExec(),
+			"gns3_vpcs":                resourceGns3Vpcs(),
+			"gns3_dynamips_router":     resourceGns3DynamipsRouter(),
+			"gns3_iou_node":            resourceGns3IOUNode(),
+			"gns3_ethernet_hub":        resourceGns3EthernetHub(),
+			"gns3_nat":                 resourceGns3Nat(),
+			"gns3_frame_relay_switch":  resourceGns3FrameRelaySwitch(),
+			"gns3_atm_switch":          resourceGns3AtmSwitch(),
+			"gns3_virtualbox_node":     resourceGns3VirtualBoxNode(),
+			"gns3_vmware_node":         resourceGns3VMwareNode(),
+			"gns3_template_definition": resourceGns3TemplateDefinition(),
+			"gns3_image":               resourceGns3Image(),
+			"gns3_docker_image":        resourceGns3DockerImage(),
+			"gns3_compute":             resourceGns3Compute(),
+			"gns3_vm_settings":         resourceGns3VMSettings(),
+			"gns3_server_settings":     resourceGns3ServerSettings(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"gns3_template_id": dataSourceGns3TemplateID(),
-			"gns3_node_id":     dataSourceGns3NodeID(),
-			"gns3_link_id":     dataSourceGns3LinkID(),
+			"gns3_template_id":    dataSourceGns3TemplateID(),
+			"gns3_node_id":        dataSourceGns3NodeID(),
+			"gns3_link_id":        dataSourceGns3LinkID(),
+			"gns3_virtualbox_vms": dataSourceGns3VirtualBoxVMs(),
+			"gns3_vmware_vms":     dataSourceGns3VMwareVMs(),
+			"gns3_templates":      dataSourceGns3Templates(),
+			"gns3_template":       dataSourceGns3TemplateDetails(),
+			"gns3_images":         dataSourceGns3Images(),
+			"gns3_computes":       dataSourceGns3Computes(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -46,8 +96,13 @@ func Provider() *schema.Provider {
 // providerConfigure initializes the provider with the GNS3 host configuration.
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := &ProviderConfig{
-		Host:   d.Get("host").(string),
-		APIURL: d.Get("host").(string),
+		Host:         d.Get("host").(string),
+		APIURL:       d.Get("host").(string),
+		IOURCContent: d.Get("iourc_content").(string),
+
+		PlacementPolicy: d.Get("placement_policy").(string),
+		ComputeLabels:   parseComputeLabels(d.Get("compute_labels").(map[string]interface{})),
+		placement:       &computePlacement{reserved: map[string]int64{}},
 	}
 
 	log.Printf("[INFO] Terraform GNS3 Provider configured with host: %s", config.Host)
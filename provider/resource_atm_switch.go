@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// AtmSwitch represents a GNS3 ATM switch node API request/response.
+type AtmSwitch struct {
+	Name       string                 `json:"name"`
+	NodeType   string                 `json:"node_type"`
+	ComputeID  string                 `json:"compute_id,omitempty"`
+	NodeID     string                 `json:"node_id,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	X          int                    `json:"x,omitempty"`
+	Y          int                    `json:"y,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// atmMappingPattern accepts VP switching (<port>:<vpi>) and VC switching (<port>:<vpi>:<vci>).
+var atmMappingPattern = regexp.MustCompile(`^\d+:\d+(:\d+)?$`)
+
+// resourceGns3AtmSwitch defines the Terraform resource schema for GNS3 ATM switches.
+func resourceGns3AtmSwitch() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3AtmSwitchCreate,
+		Read:   resourceGns3AtmSwitchRead,
+		Update: resourceGns3AtmSwitchUpdate,
+		Delete: resourceGns3AtmSwitchDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3AtmSwitchImporter,
+		},
+		CustomizeDiff: computePlacementCustomizeDiff(""),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project ID where the ATM switch is deployed.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the ATM switch node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the ATM switch node is running.",
+			},
+			"placement": placementSchema(),
+			"mappings": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ValidateFunc: validateSwitchMappings(atmMappingPattern, "<port>:<vpi> or <port>:<vpi>:<vci>"),
+				Description:  "VPI/VCI mappings in the form \"<port>:<vpi>:<vci>\" = \"<port>:<vpi>:<vci>\", or \"<port>:<vpi>\" = \"<port>:<vpi>\" for VP switching.",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the ATM switch node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the ATM switch node in GNS3 GUI.",
+			},
+			"status": builtinNodeStatusSchema(),
+			"atm_switch_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ATM switch node's ID assigned by GNS3.",
+			},
+		},
+	}
+}
+
+func resourceGns3AtmSwitchCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
+
+	sw := AtmSwitch{
+		Name:      d.Get("name").(string),
+		NodeType:  "atm_switch",
+		ComputeID: computeID,
+		X:         d.Get("x").(int),
+		Y:         d.Get("y").(int),
+		Properties: map[string]interface{}{
+			"mappings": d.Get("mappings").(map[string]interface{}),
+		},
+	}
+
+	data, err := json.Marshal(sw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ATM switch data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("error creating GNS3 ATM switch: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errResp map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("failed to create ATM switch, status code: %d, error: %v", resp.StatusCode, errResp)
+	}
+
+	var createdSwitch AtmSwitch
+	if err := json.NewDecoder(resp.Body).Decode(&createdSwitch); err != nil {
+		return fmt.Errorf("failed to decode ATM switch response: %s", err)
+	}
+
+	if createdSwitch.NodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+
+	d.SetId(createdSwitch.NodeID)
+	d.Set("atm_switch_id", createdSwitch.NodeID)
+
+	return resourceGns3AtmSwitchRead(d, meta)
+}
+
+func resourceGns3AtmSwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	switchID := d.Id()
+
+	updateData := map[string]interface{}{}
+
+	if d.HasChange("name") {
+		updateData["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("x") {
+		updateData["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		updateData["y"] = d.Get("y").(int)
+	}
+	if d.HasChange("mappings") {
+		updateData["properties"] = map[string]interface{}{
+			"mappings": d.Get("mappings").(map[string]interface{}),
+		}
+	}
+
+	if len(updateData) > 0 {
+		if err := updateNode(host, projectID, switchID, updateData); err != nil {
+			return err
+		}
+	}
+
+	return resourceGns3AtmSwitchRead(d, meta)
+}
+
+func resourceGns3AtmSwitchRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("status", node["status"])
+	d.Set("atm_switch_id", nodeID)
+	if err := d.Set("mappings", readSwitchMappings(node)); err != nil {
+		return fmt.Errorf("failed to set mappings: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGns3AtmSwitchDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for ATM switch: %s", err)
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete ATM switch: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete ATM switch, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3AtmSwitchImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
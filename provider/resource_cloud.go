@@ -18,6 +18,7 @@ type Cloud struct {
 	NodeType  string `json:"node_type"`
 	ComputeID string `json:"compute_id,omitempty"`
 	NodeID    string `json:"node_id,omitempty"`
+	Status    string `json:"status,omitempty"`
 	X         int    `json:"x,omitempty"`
 	Y         int    `json:"y,omitempty"`
 }
@@ -31,6 +32,7 @@ func resourceGns3Cloud() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceGns3CloudImporter,
 		},
+		CustomizeDiff: computePlacementCustomizeDiff(""),
 
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -44,11 +46,14 @@ func resourceGns3Cloud() *schema.Resource {
 				Description: "Name of the cloud node.",
 			},
 			"compute_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "local",
-				Description: "Compute ID where the cloud node is running.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the cloud node is running.",
 			},
+			"placement": placementSchema(),
 			"x": { // ✅ Added X coordinate support
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -59,6 +64,7 @@ func resourceGns3Cloud() *schema.Resource {
 				Optional:    true,
 				Description: "Y position of the cloud node in GNS3 GUI.",
 			},
+			"status": builtinNodeStatusSchema(),
 			"cloud_id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -73,7 +79,10 @@ func resourceGns3CloudCreate(d *schema.ResourceData, meta interface{}) error {
 	host := config.Host
 	projectID := d.Get("project_id").(string)
 	name := d.Get("name").(string)
-	computeID := d.Get("compute_id").(string)
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
 	x := d.Get("x").(int) // ✅ Retrieve X coordinate
 	y := d.Get("y").(int) // ✅ Retrieve Y coordinate
 
@@ -114,7 +123,8 @@ func resourceGns3CloudCreate(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(createdCloud.NodeID)
 	d.Set("cloud_id", createdCloud.NodeID)
-	return nil
+
+	return resourceGns3CloudRead(d, meta)
 }
 
 // Update function for modifying existing cloud nodes
@@ -130,10 +140,6 @@ func resourceGns3CloudUpdate(d *schema.ResourceData, meta interface{}) error {
 		updateData["name"] = d.Get("name").(string)
 	}
 
-	if d.HasChange("compute_id") {
-		updateData["compute_id"] = d.Get("compute_id").(string)
-	}
-
 	if d.HasChange("x") {
 		updateData["x"] = d.Get("x").(int) // ✅ Update X coordinate
 	}
@@ -143,7 +149,7 @@ func resourceGns3CloudUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if len(updateData) == 0 {
-		return nil
+		return resourceGns3CloudRead(d, meta)
 	}
 
 	updateBody, err := json.Marshal(updateData)
@@ -197,6 +203,12 @@ func resourceGns3CloudRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("unexpected read status %d: %s", resp.StatusCode, body)
 	}
 
+	var node map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		return fmt.Errorf("failed to decode cloud node: %s", err)
+	}
+	d.Set("status", node["status"])
+
 	return nil
 }
 
@@ -0,0 +1,350 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// getCompute fetches a compute from the controller. found is false when it doesn't exist.
+func getCompute(host, computeID string) (map[string]interface{}, bool, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v2/computes/%s", host, computeID))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read compute %s: %s", computeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, false, fmt.Errorf("failed to read compute %s, status code: %d, response: %s", computeID, resp.StatusCode, string(body))
+	}
+
+	var compute map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&compute); err != nil {
+		return nil, false, fmt.Errorf("failed to decode compute %s: %s", computeID, err)
+	}
+	return compute, true, nil
+}
+
+// listComputes fetches every compute registered on the controller.
+func listComputes(host string) ([]map[string]interface{}, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v2/computes", host))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list computes: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list computes, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var computes []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&computes); err != nil {
+		return nil, fmt.Errorf("failed to decode compute list: %s", err)
+	}
+	return computes, nil
+}
+
+// computeInterfaces lists the network interfaces of a compute through the controller.
+func computeInterfaces(host, computeID string) ([]map[string]interface{}, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v2/computes/%s/network/interfaces", host, computeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interfaces of compute %s: %s", computeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list interfaces of compute %s, status code: %d, response: %s", computeID, resp.StatusCode, string(body))
+	}
+
+	var interfaces []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&interfaces); err != nil {
+		return nil, fmt.Errorf("failed to decode interfaces of compute %s: %s", computeID, err)
+	}
+	return interfaces, nil
+}
+
+// computeStatusSchema describes the usage and capabilities reported for a compute.
+func computeStatusSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"connected": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Whether the controller is connected to the compute.",
+		},
+		"cpu_usage_percent": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Current CPU usage of the compute.",
+		},
+		"memory_usage_percent": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Current memory usage of the compute.",
+		},
+		"capabilities": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Version, platform and resources of the compute.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"version":    {Type: schema.TypeString, Computed: true},
+					"platform":   {Type: schema.TypeString, Computed: true},
+					"node_types": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					"cpus":       {Type: schema.TypeInt, Computed: true},
+					"memory":     {Type: schema.TypeInt, Computed: true, Description: "Total memory in bytes."},
+					"disk_size":  {Type: schema.TypeInt, Computed: true, Description: "Total disk size in bytes."},
+				},
+			},
+		},
+	}
+}
+
+// flattenComputeStatus converts the usage and capabilities of a compute for computeStatusSchema.
+func flattenComputeStatus(compute map[string]interface{}) map[string]interface{} {
+	caps, _ := compute["capabilities"].(map[string]interface{})
+	nodeTypes := []string{}
+	if raw, ok := caps["node_types"].([]interface{}); ok {
+		for _, t := range raw {
+			if s, ok := t.(string); ok {
+				nodeTypes = append(nodeTypes, s)
+			}
+		}
+	}
+	number := func(v interface{}) int {
+		f, _ := v.(float64)
+		return int(f)
+	}
+	version, _ := caps["version"].(string)
+	platform, _ := caps["platform"].(string)
+	connected, _ := compute["connected"].(bool)
+	cpu, _ := compute["cpu_usage_percent"].(float64)
+	memory, _ := compute["memory_usage_percent"].(float64)
+
+	status := map[string]interface{}{
+		"connected":            connected,
+		"cpu_usage_percent":    cpu,
+		"memory_usage_percent": memory,
+		"capabilities": []interface{}{
+			map[string]interface{}{
+				"version":    version,
+				"platform":   platform,
+				"node_types": nodeTypes,
+				"cpus":       number(caps["cpus"]),
+				"memory":     number(caps["memory"]),
+				"disk_size":  number(caps["disk_size"]),
+			},
+		},
+	}
+	if len(caps) == 0 {
+		status["capabilities"] = []interface{}{}
+	}
+	return status
+}
+
+// resourceGns3Compute defines the Terraform resource schema for computes registered on the controller.
+func resourceGns3Compute() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"compute_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "ID of the compute. Generated when not set.",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Display name of the compute. GNS3 derives one from the host when not set.",
+		},
+		"protocol": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "http",
+			ValidateFunc: validation.StringInSlice([]string{"http", "https"}, false),
+			Description:  "Protocol used to reach the compute.",
+		},
+		"host": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Address of the compute server.",
+		},
+		"port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      3080,
+			ValidateFunc: validation.IsPortNumber,
+			Description:  "Port of the compute server.",
+		},
+		"user": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "User for HTTP basic authentication on the compute.",
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "Password for HTTP basic authentication on the compute.",
+		},
+	}
+	for key, value := range computeStatusSchema() {
+		s[key] = value
+	}
+
+	return &schema.Resource{
+		Create: resourceGns3ComputeCreate,
+		Read:   resourceGns3ComputeRead,
+		Update: resourceGns3ComputeUpdate,
+		Delete: resourceGns3ComputeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: s,
+	}
+}
+
+// computePayload builds the /v2/computes request body from the resource data.
+func computePayload(d *schema.ResourceData) map[string]interface{} {
+	payload := map[string]interface{}{
+		"protocol": d.Get("protocol").(string),
+		"host":     d.Get("host").(string),
+		"port":     d.Get("port").(int),
+	}
+	if v, ok := d.GetOk("name"); ok {
+		payload["name"] = v.(string)
+	}
+	if v, ok := d.GetOk("user"); ok {
+		payload["user"] = v.(string)
+	}
+	if v, ok := d.GetOk("password"); ok {
+		payload["password"] = v.(string)
+	}
+	return payload
+}
+
+func resourceGns3ComputeCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	computeID := d.Get("compute_id").(string)
+	if computeID == "" {
+		generated, err := uuid.GenerateUUID()
+		if err != nil {
+			return fmt.Errorf("failed to generate compute ID: %s", err)
+		}
+		computeID = generated
+	}
+
+	payload := computePayload(d)
+	payload["compute_id"] = computeID
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal compute data: %s", err)
+	}
+
+	resp, err := http.Post(fmt.Sprintf("%s/v2/computes", config.Host), "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create compute: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create compute, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId(computeID)
+	return resourceGns3ComputeRead(d, meta)
+}
+
+func resourceGns3ComputeRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	compute, found, err := getCompute(config.Host, d.Id())
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("compute_id", d.Id())
+	d.Set("name", compute["name"])
+	d.Set("protocol", compute["protocol"])
+	d.Set("host", compute["host"])
+	if v, ok := compute["port"].(float64); ok {
+		d.Set("port", int(v))
+	}
+	if v, ok := compute["user"].(string); ok {
+		d.Set("user", v)
+	}
+	for key, value := range flattenComputeStatus(compute) {
+		if err := d.Set(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %s", key, err)
+		}
+	}
+	return nil
+}
+
+func resourceGns3ComputeUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	data, err := json.Marshal(computePayload(d))
+	if err != nil {
+		return fmt.Errorf("failed to marshal compute data: %s", err)
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/v2/computes/%s", config.Host, d.Id()), bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create update request for compute: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update compute: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update compute, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	return resourceGns3ComputeRead(d, meta)
+}
+
+func resourceGns3ComputeDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/v2/computes/%s", config.Host, d.Id()), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for compute: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete compute: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete compute, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
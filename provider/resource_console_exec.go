@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// defaultConsolePrompt matches the usual user, enable and shell prompts: a line made
+// of a single word ending in >, # or $. It is anchored to the start of the line so
+// output such as "% Invalid input" or "# comment" lines does not end a command early.
+const defaultConsolePrompt = `(?m)^\S+[>#$]\s?$`
+
+// resourceGns3ConsoleExec defines a resource that runs CLI commands on a node's telnet console.
+func resourceGns3ConsoleExec() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3ConsoleExecCreate,
+		Read:   resourceGns3ConsoleExecRead,
+		Delete: resourceGns3ConsoleExecDelete,
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The project ID of the node.",
+			},
+			"node_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The node whose console receives the commands. The node must be started.",
+			},
+			"prompt": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      defaultConsolePrompt,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Pattern expected on the console before the first command is sent.",
+			},
+			"connect_timeout_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum seconds to wait for the console and the initial prompt.",
+			},
+			"command": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "Commands sent to the console in order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"command": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The command line to send.",
+						},
+						"expect": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultConsolePrompt,
+							ValidateFunc: validation.StringIsValidRegExp,
+							Description:  "Pattern that marks the end of the command output.",
+						},
+						"timeout_seconds": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      30,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Maximum seconds to wait for the expect pattern.",
+						},
+					},
+				},
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that re-run the commands when they change.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"output": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Console output captured while running the commands.",
+			},
+		},
+	}
+}
+
+func resourceGns3ConsoleExecCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Get("node_id").(string)
+	connectTimeout := time.Duration(d.Get("connect_timeout_seconds").(int)) * time.Second
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("node %s not found in project %s", nodeID, projectID)
+	}
+	if s, _ := node["status"].(string); s != "started" {
+		return fmt.Errorf("node %s must be started to run console commands, status is %q", nodeID, s)
+	}
+
+	session, err := openNodeConsole(host, node, connectTimeout)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	// Wake the console up and wait for a prompt before sending anything
+	prompt := regexp.MustCompile(d.Get("prompt").(string))
+	if _, err := session.expect(prompt, connectTimeout, true); err != nil {
+		return fmt.Errorf("no prompt on node %s console: %s", nodeID, err)
+	}
+	if err := session.drain(connectTimeout); err != nil {
+		return fmt.Errorf("failed to read node %s console: %s", nodeID, err)
+	}
+
+	var output strings.Builder
+	for i, raw := range d.Get("command").([]interface{}) {
+		cmd := raw.(map[string]interface{})
+		line := cmd["command"].(string)
+		expect := regexp.MustCompile(cmd["expect"].(string))
+		timeout := time.Duration(cmd["timeout_seconds"].(int)) * time.Second
+
+		log.Printf("[DEBUG] Sending console command %d to node %s: %s", i, nodeID, line)
+		if err := session.send(line); err != nil {
+			return fmt.Errorf("failed to send command %q to node %s: %s", line, nodeID, err)
+		}
+		out, err := session.expect(expect, timeout, false)
+		output.WriteString(out)
+		if err != nil {
+			return fmt.Errorf("command %q on node %s: %s\n%s", line, nodeID, err, out)
+		}
+	}
+
+	d.SetId(id.UniqueId())
+	d.Set("output", output.String())
+	return nil
+}
+
+func resourceGns3ConsoleExecRead(d *schema.ResourceData, meta interface{}) error {
+	// Commands are one-shot; the captured output stays in state until re-run.
+	return nil
+}
+
+func resourceGns3ConsoleExecDelete(d *schema.ResourceData, meta interface{}) error {
+	// Nothing to undo on the device; only forget the execution.
+	d.SetId("")
+	return nil
+}
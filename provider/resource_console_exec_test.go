@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestDefaultConsolePrompt(t *testing.T) {
+	prompt := regexp.MustCompile(defaultConsolePrompt)
+	cases := []struct {
+		name   string
+		output string
+		match  bool
+	}{
+		{"user prompt", "\r\nRouter>", true},
+		{"enable prompt", "show clock\r\n*10:00:00 UTC Mon Oct 19 2026\r\nRouter#", true},
+		{"shell prompt", "ls\r\nbin etc\r\nuser@host:~$ ", true},
+		{"root shell prompt", "\r\nroot@box:/# ", true},
+		{"IOS error", "show foo\r\n% Invalid input detected at '^' marker.\r\n", false},
+		{"percent line", "\r\n%", false},
+		{"comment line", "cat run.cfg\r\n# interfaces\r\n", false},
+		{"bare hash", "\r\n#", false},
+		{"prompt inside a line", "Router#show version", false},
+		{"unfinished output", "Building configuration...\r\n", false},
+	}
+	for _, c := range cases {
+		if got := prompt.MatchString(c.output); got != c.match {
+			t.Errorf("%s: match(%q) = %v, want %v", c.name, c.output, got, c.match)
+		}
+	}
+}
@@ -7,29 +7,86 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // DockerProperties holds Docker-specific options for a node.
 type DockerProperties struct {
-	Image        string   `json:"image"`
-	Environment  *string  `json:"environment,omitempty"`
-	ConsoleType  string   `json:"console_type"`
-	ExtraVolumes []string `json:"extra_volumes,omitempty"`
-	StartCommand *string  `json:"start_command,omitempty"`
+	Image             string   `json:"image"`
+	Adapters          int      `json:"adapters"`
+	Environment       *string  `json:"environment,omitempty"`
+	ConsoleResolution string   `json:"console_resolution,omitempty"`
+	ConsoleHTTPPort   int      `json:"console_http_port,omitempty"`
+	ConsoleHTTPPath   string   `json:"console_http_path,omitempty"`
+	MacAddress        string   `json:"mac_address,omitempty"`
+	Usage             string   `json:"usage,omitempty"`
+	ExtraVolumes      []string `json:"extra_volumes,omitempty"`
+	StartCommand      *string  `json:"start_command,omitempty"`
 }
 
 // DockerNode represents the JSON payload for creating a Docker node.
 type DockerNode struct {
-	Name       string           `json:"name"`
-	NodeType   string           `json:"node_type"`
-	ComputeID  string           `json:"compute_id,omitempty"`
-	Properties DockerProperties `json:"properties"`
-	NodeID     string           `json:"node_id,omitempty"`
-	X          int              `json:"x,omitempty"` // Added X coordinate
-	Y          int              `json:"y,omitempty"` // Added Y coordinate
+	Name           string           `json:"name"`
+	NodeType       string           `json:"node_type"`
+	ComputeID      string           `json:"compute_id,omitempty"`
+	ConsoleType    string           `json:"console_type"`
+	Aux            int              `json:"aux,omitempty"`
+	CustomAdapters []interface{}    `json:"custom_adapters,omitempty"`
+	Properties     DockerProperties `json:"properties"`
+	NodeID         string           `json:"node_id,omitempty"`
+	X              int              `json:"x,omitempty"` // Added X coordinate
+	Y              int              `json:"y,omitempty"` // Added Y coordinate
+}
+
+// dockerPropertyKeys are the scalar Docker properties managed one-to-one by the resource.
+// image is not among them: the compute cannot change the image of a container in place.
+var dockerPropertyKeys = []string{
+	"adapters", "console_resolution", "console_http_port", "console_http_path", "mac_address", "usage", "start_command",
+}
+
+// dockerImageRef normalizes an image name by adding the implicit :latest tag.
+func dockerImageRef(image string) string {
+	if image == "" || strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") || strings.Contains(image, "@") {
+		return image
+	}
+	return image + ":latest"
+}
+
+// formatDockerEnvironment renders the environment map the way GNS3 stores it:
+// one KEY=VALUE per line, sorted so the string is stable.
+func formatDockerEnvironment(env map[string]interface{}) string {
+	lines := make([]string, 0, len(env))
+	for key, value := range env {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, value.(string)))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// parseDockerEnvironment is the inverse of formatDockerEnvironment. Values are kept
+// exactly as written, surrounding spaces included, so they do not drift from the
+// configuration; only the line ending and blank lines are dropped.
+func parseDockerEnvironment(raw string) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) == 2 {
+			env[key] = parts[1]
+		} else {
+			env[key] = ""
+		}
+	}
+	return env
 }
 
 func resourceGns3Docker() *schema.Resource {
@@ -41,6 +98,11 @@ func resourceGns3Docker() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceGns3DockerImporter,
 		},
+		CustomizeDiff: customdiff.All(
+			resourceGns3DockerCustomizeDiff,
+			computePlacementCustomizeDiff(""),
+			waitForCustomizeDiff("start"),
+		),
 
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -54,16 +116,81 @@ func resourceGns3Docker() *schema.Resource {
 				Description: "The name of the Docker node.",
 			},
 			"compute_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "local",
-				Description: "The compute ID (default: 'local').",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "The compute ID (default: 'local').",
 			},
+			"placement": placementSchema(),
 			"image": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true, // Ensures re-creation when image changes
+				ForceNew:    true,
 				Description: "The Docker image name. The image must be available in GNS3.",
+				// GNS3 appends :latest to untagged images
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return dockerImageRef(old) == dockerImageRef(new)
+				},
+			},
+			"adapters": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(0, 99),
+				Description:  "Number of network adapters.",
+			},
+			"console_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				ValidateFunc: validation.StringInSlice([]string{"telnet", "vnc", "http", "https", "none"}, false),
+				Description:  "Console type: telnet, vnc, http, https or none.",
+			},
+			"console_resolution": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "1024x768",
+				Description: "Screen resolution of the VNC console.",
+			},
+			"console_http_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      80,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "Port of the web interface inside the container (http/https consoles).",
+			},
+			"console_http_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/",
+				Description: "Path of the web interface inside the container (http/https consoles).",
+			},
+			"aux": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Auxiliary telnet console port. Allocated by GNS3 when not set.",
+			},
+			"mac_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Base MAC address of the container's adapters.",
+			},
+			"usage": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Usage notes shown to users of the node.",
+			},
+			"custom_adapters": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+				Description:      "JSON list of per-adapter settings (adapter_number, mac_address, port_name).",
 			},
 			"environment": {
 				Type:        schema.TypeMap,
@@ -105,8 +232,14 @@ func resourceGns3Docker() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     true,
+				Deprecated:  "Use status instead.",
 				Description: "Whether to start the Docker container after creation.",
 			},
+			"status":           nodeStatusSchema(),
+			"wait_for":         waitForSchema(),
+			"files":            nodeFilesSchema(),
+			"reload_on_change": reloadOnChangeSchema(),
+			"file_hashes":      fileHashesSchema(),
 		},
 	}
 }
@@ -116,20 +249,18 @@ func resourceGns3DockerCreate(d *schema.ResourceData, meta interface{}) error {
 	host := config.Host
 	projectID := d.Get("project_id").(string)
 	name := d.Get("name").(string)
-	computeID := d.Get("compute_id").(string)
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
 	image := d.Get("image").(string)
 	x := d.Get("x").(int)
 	y := d.Get("y").(int)
 
-	// Convert environment map into a single string format (comma-separated key=value pairs)
+	// GNS3 stores the environment as newline-separated KEY=VALUE pairs
 	var envStr *string
 	if v, ok := d.GetOk("environment"); ok {
-		envVars := v.(map[string]interface{})
-		envList := []string{}
-		for key, value := range envVars {
-			envList = append(envList, fmt.Sprintf("%s=%s", key, value.(string)))
-		}
-		envFormatted := strings.Join(envList, ",")
+		envFormatted := formatDockerEnvironment(v.(map[string]interface{}))
 		envStr = &envFormatted
 	}
 
@@ -148,19 +279,34 @@ func resourceGns3DockerCreate(d *schema.ResourceData, meta interface{}) error {
 		startCommand = &cmd
 	}
 
+	var customAdapters []interface{}
+	if v, ok := d.GetOk("custom_adapters"); ok {
+		if err := json.Unmarshal([]byte(v.(string)), &customAdapters); err != nil {
+			return fmt.Errorf("failed to parse custom_adapters: %s", err)
+		}
+	}
+
 	// Build the payload for the Docker node
 	dockerNode := DockerNode{
-		Name:      name,
-		NodeType:  "docker",
-		ComputeID: computeID,
-		X:         x,
-		Y:         y,
+		Name:           name,
+		NodeType:       "docker",
+		ComputeID:      computeID,
+		ConsoleType:    d.Get("console_type").(string),
+		Aux:            d.Get("aux").(int),
+		CustomAdapters: customAdapters,
+		X:              x,
+		Y:              y,
 		Properties: DockerProperties{
-			Image:        image,
-			Environment:  envStr,
-			ConsoleType:  "none",
-			ExtraVolumes: extraVolumes,
-			StartCommand: startCommand,
+			Image:             image,
+			Adapters:          d.Get("adapters").(int),
+			Environment:       envStr,
+			ConsoleResolution: d.Get("console_resolution").(string),
+			ConsoleHTTPPort:   d.Get("console_http_port").(int),
+			ConsoleHTTPPath:   d.Get("console_http_path").(string),
+			MacAddress:        d.Get("mac_address").(string),
+			Usage:             d.Get("usage").(string),
+			ExtraVolumes:      extraVolumes,
+			StartCommand:      startCommand,
 		},
 	}
 
@@ -204,26 +350,20 @@ func resourceGns3DockerCreate(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(createdDocker.NodeID)
 	d.Set("docker_id", createdDocker.NodeID)
 
-	// Optionally start the container
-	if d.Get("start").(bool) {
-		startURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/start", host, projectID, createdDocker.NodeID)
-		startReq, err := http.NewRequest("POST", startURL, nil)
-		if err != nil {
-			return fmt.Errorf("failed to build start request: %s", err)
-		}
-		startResp, err := client.Do(startReq)
-		if err != nil {
-			return fmt.Errorf("failed to start docker node: %s", err)
-		}
-		defer startResp.Body.Close()
+	// Upload configuration files before the first start
+	if err := uploadNodeFiles(d, host, projectID, createdDocker.NodeID); err != nil {
+		return err
+	}
 
-		if startResp.StatusCode != http.StatusOK {
-			startBody, _ := ioutil.ReadAll(startResp.Body)
-			return fmt.Errorf("failed to start docker node, status code: %d, response: %s", startResp.StatusCode, string(startBody))
-		}
+	// Bring the container to the requested status (new nodes are created stopped)
+	if err := setNodeStatus(host, projectID, createdDocker.NodeID, "stopped", desiredNodeStatus(d, "start")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, createdDocker.NodeID); err != nil {
+		return err
 	}
 
-	return nil
+	return resourceGns3DockerRead(d, meta)
 }
 
 func resourceGns3DockerRead(d *schema.ResourceData, meta interface{}) error {
@@ -248,7 +388,59 @@ func resourceGns3DockerRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("failed to read Docker node, status code: %d", resp.StatusCode)
 	}
 
-	// Optionally, you can decode the response to update state further.
+	var node map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		return fmt.Errorf("failed to decode Docker node: %s", err)
+	}
+	d.Set("name", node["name"])
+	d.Set("status", node["status"])
+	d.Set("docker_id", nodeID)
+	if v, ok := node["compute_id"].(string); ok && v != "" {
+		d.Set("compute_id", v)
+	}
+	d.Set("console_type", node["console_type"])
+	if v, ok := node["aux"].(float64); ok {
+		d.Set("aux", int(v))
+	}
+	if v, ok := node["x"].(float64); ok {
+		d.Set("x", int(v))
+	}
+	if v, ok := node["y"].(float64); ok {
+		d.Set("y", int(v))
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	setNodeProperties(d, props, dockerPropertyKeys)
+	if v, ok := props["image"].(string); ok {
+		d.Set("image", v)
+	}
+
+	env, _ := props["environment"].(string)
+	if err := d.Set("environment", parseDockerEnvironment(env)); err != nil {
+		return fmt.Errorf("failed to set environment: %s", err)
+	}
+	volumes, _ := props["extra_volumes"].([]interface{})
+	if err := d.Set("extra_volumes", volumes); err != nil {
+		return fmt.Errorf("failed to set extra_volumes: %s", err)
+	}
+
+	customAdapters, ok := node["custom_adapters"]
+	if !ok || customAdapters == nil {
+		customAdapters = props["custom_adapters"]
+	}
+	if customAdapters == nil {
+		customAdapters = []interface{}{}
+	}
+	encoded, err := json.Marshal(customAdapters)
+	if err != nil {
+		return fmt.Errorf("failed to encode custom_adapters: %s", err)
+	}
+	d.Set("custom_adapters", string(encoded))
+
+	if err := readNodeFiles(d, host, projectID, nodeID, node); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -258,45 +450,83 @@ func resourceGns3DockerUpdate(d *schema.ResourceData, meta interface{}) error {
 	projectID := d.Get("project_id").(string)
 	nodeID := d.Id()
 
-	// Build the updated payload.
-	updateData := make(map[string]interface{})
+	oldStatus, _ := d.GetChange("status")
+	currentStatus := oldStatus.(string)
+	desired := desiredNodeStatus(d, "")
+
+	// Property changes make GNS3 recreate the container, which needs it stopped
+	properties := map[string]interface{}{}
+	for _, key := range dockerPropertyKeys {
+		if d.HasChange(key) {
+			properties[key] = d.Get(key)
+		}
+	}
 	if d.HasChange("environment") {
-		envVars := d.Get("environment").(map[string]interface{})
-		envList := []string{}
-		for key, value := range envVars {
-			envList = append(envList, fmt.Sprintf("%s=%s", key, value.(string)))
+		properties["environment"] = formatDockerEnvironment(d.Get("environment").(map[string]interface{}))
+	}
+	if d.HasChange("extra_volumes") {
+		volumes := []string{}
+		for _, vol := range d.Get("extra_volumes").([]interface{}) {
+			volumes = append(volumes, vol.(string))
 		}
-		envFormatted := strings.Join(envList, ",")
-		updateData["environment"] = envFormatted
+		properties["extra_volumes"] = volumes
 	}
-	// Note: Image is ForceNew so we do not update it.
-	// Also, extra_volumes, x, and y are typically not updated dynamically, but you could add them if needed.
 
-	data, err := json.Marshal(updateData)
-	if err != nil {
-		return fmt.Errorf("failed to marshal update data: %s", err)
+	updateData := map[string]interface{}{}
+	if d.HasChange("name") {
+		updateData["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("console_type") {
+		updateData["console_type"] = d.Get("console_type").(string)
+	}
+	if d.HasChange("aux") {
+		if v, ok := d.GetOk("aux"); ok {
+			updateData["aux"] = v.(int)
+		}
+	}
+	if d.HasChange("custom_adapters") {
+		var adapters []interface{}
+		if err := json.Unmarshal([]byte(d.Get("custom_adapters").(string)), &adapters); err != nil {
+			return fmt.Errorf("failed to parse custom_adapters: %s", err)
+		}
+		updateData["custom_adapters"] = adapters
+	}
+	if d.HasChange("x") {
+		updateData["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		updateData["y"] = d.Get("y").(int)
 	}
 
-	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
-	if err != nil {
-		return fmt.Errorf("failed to create update request: %s", err)
+	if len(properties) > 0 || d.HasChanges("console_type", "custom_adapters") {
+		if currentStatus != "stopped" {
+			if err := nodeAction(host, projectID, nodeID, "stop"); err != nil {
+				return err
+			}
+			if desired == "" {
+				desired = currentStatus
+			}
+			currentStatus = "stopped"
+		}
+	}
+	if len(properties) > 0 {
+		updateData["properties"] = properties
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to update Docker node: %s", err)
+	if len(updateData) > 0 {
+		if err := updateNode(host, projectID, nodeID, updateData); err != nil {
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("failed to update Docker node, status code: %d, response: %s", resp.StatusCode, string(body))
+	if d.HasChanges("files") {
+		if err := uploadNodeFiles(d, host, projectID, nodeID); err != nil {
+			return err
+		}
 	}
-	if d.HasChange("start_command") {
-		updateData["start_command"] = d.Get("start_command").(string)
+
+	if err := setNodeStatus(host, projectID, nodeID, currentStatus, desired); err != nil {
+		return err
 	}
 
 	return resourceGns3DockerRead(d, meta)
@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// listDockerImages returns the normalized references of the Docker images on a compute.
+func listDockerImages(host, computeID string) ([]string, error) {
+	apiURL := fmt.Sprintf("%s/v2/compute/docker/images", host)
+	if computeID != "" && computeID != "local" {
+		apiURL = fmt.Sprintf("%s/v2/computes/%s/docker/images", host, computeID)
+	}
+
+	resp, err := http.Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker images: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list Docker images, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var listed []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&listed); err != nil {
+		return nil, fmt.Errorf("failed to decode Docker image list: %s", err)
+	}
+	images := make([]string, 0, len(listed))
+	for _, image := range listed {
+		if name, ok := image["image"].(string); ok {
+			images = append(images, dockerImageRef(name))
+		}
+	}
+	return images, nil
+}
+
+// dockerImageAvailable reports whether the compute already has the image.
+func dockerImageAvailable(host, computeID, image string) (bool, error) {
+	images, err := listDockerImages(host, computeID)
+	if err != nil {
+		return false, err
+	}
+	return containsString(images, dockerImageRef(image)), nil
+}
+
+// resourceGns3DockerImage defines the Terraform resource schema for Docker images pulled on a compute.
+func resourceGns3DockerImage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3DockerImageCreate,
+		Read:   resourceGns3DockerImageRead,
+		Delete: resourceGns3DockerImageDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Image to pull, e.g. nginx:1.27. Untagged names get :latest.",
+			},
+			"compute_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "local",
+				Description: "Compute to pull the image on.",
+			},
+			"timeout_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      900,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How long to wait for the pull to finish.",
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Reference of the pulled image; use it as gns3_docker.image to order nodes after the pull.",
+			},
+		},
+	}
+}
+
+// dockerPullCleanupTimeout bounds the deletion of the scratch project used for a pull.
+const dockerPullCleanupTimeout = 60 * time.Second
+
+// pullDockerImage makes the compute pull an image. GNS3 has no pull endpoint, but
+// creating a Docker node pulls its image before the create call returns, so a
+// node is created in a scratch project that is deleted afterwards.
+func pullDockerImage(host, computeID, image string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+
+	projectData, _ := json.Marshal(map[string]interface{}{
+		"name": fmt.Sprintf("terraform-docker-pull-%s", id.UniqueId()),
+	})
+	resp, err := client.Post(fmt.Sprintf("%s/v2/projects", host), "application/json", bytes.NewBuffer(projectData))
+	if err != nil {
+		return fmt.Errorf("failed to create scratch project for Docker pull: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create scratch project for Docker pull, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var project map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return fmt.Errorf("failed to decode scratch project: %s", err)
+	}
+	projectID, _ := project["project_id"].(string)
+	if projectID == "" {
+		return fmt.Errorf("failed to retrieve project_id from GNS3 API response")
+	}
+
+	// The cleanup gets its own deadline, so a pull that used up the timeout does
+	// not also leave the scratch project behind.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dockerPullCleanupTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/v2/projects/%s", host, projectID), nil)
+		if err != nil {
+			return
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Printf("[WARN] Failed to delete scratch project %s after Docker pull, remove it by hand: %s", projectID, err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+			log.Printf("[WARN] Failed to delete scratch project %s after Docker pull, remove it by hand: status code %d", projectID, resp.StatusCode)
+		}
+	}()
+
+	nodeData, _ := json.Marshal(map[string]interface{}{
+		"name":         "pull",
+		"node_type":    "docker",
+		"compute_id":   computeID,
+		"console_type": "none",
+		"properties": map[string]interface{}{
+			"image":    image,
+			"adapters": 0,
+		},
+	})
+	nodeResp, err := client.Post(fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID), "application/json", bytes.NewBuffer(nodeData))
+	if err != nil {
+		return fmt.Errorf("failed to pull Docker image %s: %s", image, err)
+	}
+	defer nodeResp.Body.Close()
+	if nodeResp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(nodeResp.Body)
+		return fmt.Errorf("failed to pull Docker image %s, status code: %d, response: %s", image, nodeResp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func resourceGns3DockerImageCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	computeID := d.Get("compute_id").(string)
+	image := dockerImageRef(d.Get("name").(string))
+
+	available, err := dockerImageAvailable(host, computeID, image)
+	if err != nil {
+		return err
+	}
+	if !available {
+		timeout := time.Duration(d.Get("timeout_seconds").(int)) * time.Second
+		if err := pullDockerImage(host, computeID, image, timeout); err != nil {
+			return err
+		}
+		available, err = dockerImageAvailable(host, computeID, image)
+		if err != nil {
+			return err
+		}
+		if !available {
+			return fmt.Errorf("Docker image %s is still missing on compute %s after the pull", image, computeID)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", computeID, image))
+	d.Set("image", image)
+	return nil
+}
+
+func resourceGns3DockerImageRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	image := d.Get("image").(string)
+	if image == "" {
+		image = dockerImageRef(d.Get("name").(string))
+	}
+
+	available, err := dockerImageAvailable(config.Host, d.Get("compute_id").(string), image)
+	if err != nil {
+		return err
+	}
+	if !available {
+		// Removed from the compute; plan a new pull
+		d.SetId("")
+		return nil
+	}
+	d.Set("image", image)
+	return nil
+}
+
+// resourceGns3DockerImageDelete only removes the image from state; GNS3 has no API
+// for deleting Docker images and other nodes may still use it.
+func resourceGns3DockerImageDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+// resourceGns3DockerCustomizeDiff fails the plan when the image is not on the compute.
+// Images that are unknown at plan time, such as gns3_docker_image.x.image, are skipped,
+// and so is the check when the compute's image list cannot be read.
+func resourceGns3DockerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("image") || !d.NewValueKnown("compute_id") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChanges("image", "compute_id") {
+		return nil
+	}
+
+	config := meta.(*ProviderConfig)
+	image := d.Get("image").(string)
+	computeID := d.Get("compute_id").(string)
+	if _, auto := autoComputeLabel(computeID); auto {
+		// The compute is only picked at create time
+		return nil
+	}
+	available, err := dockerImageAvailable(config.Host, computeID, image)
+	if err != nil {
+		// The check is only an early warning; an unreachable compute must not fail the plan
+		log.Printf("[WARN] Skipping Docker image check for %s on compute %s: %s", image, computeID, err)
+		return nil
+	}
+	if !available {
+		return fmt.Errorf("Docker image %s is not available on compute %s; pull it first, e.g. with a gns3_docker_image resource", dockerImageRef(image), computeID)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDockerEnvironmentRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"empty", map[string]string{}},
+		{"plain", map[string]string{"A": "1", "B": "two"}},
+		{"empty value", map[string]string{"EMPTY": ""}},
+		{"equals in value", map[string]string{"URL": "http://x/?a=b&c=d"}},
+		{"surrounding spaces", map[string]string{"PAD": "  padded  ", "LEAD": " x", "TRAIL": "y "}},
+		{"only spaces", map[string]string{"SPACES": "   "}},
+	}
+	for _, c := range cases {
+		raw := map[string]interface{}{}
+		for key, value := range c.env {
+			raw[key] = value
+		}
+		got := parseDockerEnvironment(formatDockerEnvironment(raw))
+		if !reflect.DeepEqual(got, c.env) {
+			t.Errorf("%s: round trip = %q, want %q", c.name, got, c.env)
+		}
+	}
+}
+
+func TestParseDockerEnvironment(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want map[string]string
+	}{
+		{"sorted lines", "A=1\nB=2", map[string]string{"A": "1", "B": "2"}},
+		{"CRLF line endings", "A=1\r\nB=2\r\n", map[string]string{"A": "1", "B": "2"}},
+		{"blank lines", "\nA=1\n  \n\nB=2\n", map[string]string{"A": "1", "B": "2"}},
+		{"no equals sign", "FLAG", map[string]string{"FLAG": ""}},
+		{"value kept as written", "A= spaced ", map[string]string{"A": " spaced "}},
+	}
+	for _, c := range cases {
+		if got := parseDockerEnvironment(c.raw); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: parse(%q) = %q, want %q", c.name, c.raw, got, c.want)
+		}
+	}
+}
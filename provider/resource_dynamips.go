@@ -0,0 +1,529 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dynamipsPlatform describes the slot layout a Dynamips platform accepts.
+type dynamipsPlatform struct {
+	Slots       int
+	WICs        int
+	Slot0       []string
+	SlotModules []string
+	WICModules  []string
+}
+
+var dynamipsPlatforms = map[string]dynamipsPlatform{
+	"c7200": {
+		Slots:       7,
+		Slot0:       []string{"C7200-IO-FE", "C7200-IO-2FE", "C7200-IO-GE-E"},
+		SlotModules: []string{"PA-A1", "PA-FE-TX", "PA-2FE-TX", "PA-GE", "PA-4T+", "PA-8T", "PA-4E", "PA-8E", "PA-POS-OC3"},
+	},
+	"c3725": {
+		Slots:       3,
+		WICs:        3,
+		Slot0:       []string{"GT96100-FE"},
+		SlotModules: []string{"NM-1FE-TX", "NM-4T", "NM-16ESW"},
+		WICModules:  []string{"WIC-1T", "WIC-2T", "WIC-1ENET"},
+	},
+	"c2691": {
+		Slots:       2,
+		WICs:        3,
+		Slot0:       []string{"GT96100-FE"},
+		SlotModules: []string{"NM-1FE-TX", "NM-4T", "NM-16ESW"},
+		WICModules:  []string{"WIC-1T", "WIC-2T", "WIC-1ENET"},
+	},
+}
+
+// dynamipsPropertyKeys are the node properties managed one-to-one by the resource.
+var dynamipsPropertyKeys = []string{
+	"image", "ram", "nvram", "idlepc", "mac_addr",
+	"slot0", "slot1", "slot2", "slot3", "slot4", "slot5", "slot6",
+	"wic0", "wic1", "wic2",
+}
+
+// resourceGns3DynamipsRouter defines the Terraform resource schema for Dynamips IOS routers.
+func resourceGns3DynamipsRouter() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"project_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The project ID where the router is created.",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name of the router.",
+		},
+		"compute_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         true,
+			Default:          "local",
+			DiffSuppressFunc: suppressAutoComputeID,
+			Description:      "Compute ID where the router runs.",
+		},
+		"placement": placementSchema(),
+		"platform": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"c7200", "c3725", "c2691"}, false),
+			Description:  "Dynamips platform (c7200, c3725 or c2691).",
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "IOS image file name on the compute.",
+		},
+		"ram": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			Description: "RAM in MB. Defaults to the platform default.",
+		},
+		"nvram": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			Description: "NVRAM in KB. Defaults to the platform default.",
+		},
+		"idlepc": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Idle-PC value that stops the router from pinning a host CPU.",
+		},
+		"mac_addr": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Base MAC address of the router.",
+		},
+		"console": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			Description: "Console TCP port. Allocated by GNS3 when not set.",
+		},
+		"x": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "X position of the router in GNS3 GUI.",
+		},
+		"y": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Y position of the router in GNS3 GUI.",
+		},
+		"auto_idlepc": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Compute an idle-PC value after creation when idlepc is not set. The value is kept in state and reused.",
+		},
+		"dynamips_id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Dynamips instance ID assigned by the compute.",
+		},
+		"startup_config":   nodeConfigSchema("IOS startup configuration loaded when the router boots."),
+		"private_config":   nodeConfigSchema("IOS private configuration loaded when the router boots."),
+		"files":            nodeFilesSchema(),
+		"reload_on_change": reloadOnChangeSchema(),
+		"file_hashes":      fileHashesSchema(),
+		"status":           nodeStatusSchema(),
+		"wait_for":         waitForSchema(),
+	}
+	for i := 0; i <= 6; i++ {
+		s[fmt.Sprintf("slot%d", i)] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: fmt.Sprintf("Adapter module in slot %d (e.g. PA-FE-TX, NM-4T).", i),
+		}
+	}
+	for i := 0; i <= 2; i++ {
+		s[fmt.Sprintf("wic%d", i)] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: fmt.Sprintf("WAN interface card in WIC slot %d (c3725 and c2691 only).", i),
+		}
+	}
+
+	return &schema.Resource{
+		Create: resourceGns3DynamipsRouterCreate,
+		Read:   resourceGns3DynamipsRouterRead,
+		Update: resourceGns3DynamipsRouterUpdate,
+		Delete: resourceGns3DynamipsRouterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3DynamipsRouterImporter,
+		},
+		CustomizeDiff: customdiff.All(
+			resourceGns3DynamipsRouterCustomizeDiff,
+			computePlacementCustomizeDiff("ram"),
+			waitForCustomizeDiff(""),
+		),
+		Schema: s,
+	}
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceGns3DynamipsRouterCustomizeDiff checks the adapter modules against the platform.
+func resourceGns3DynamipsRouterCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	platformName := d.Get("platform").(string)
+	platform, ok := dynamipsPlatforms[platformName]
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i <= 6; i++ {
+		key := fmt.Sprintf("slot%d", i)
+		module := d.Get(key).(string)
+		if module == "" || !d.NewValueKnown(key) {
+			continue
+		}
+		if i >= platform.Slots {
+			return fmt.Errorf("%s: platform %s has only %d slots", key, platformName, platform.Slots)
+		}
+		allowed := platform.SlotModules
+		if i == 0 {
+			allowed = platform.Slot0
+		}
+		if !containsString(allowed, module) {
+			return fmt.Errorf("%s: module %q is not valid for %s, expected one of %s", key, module, platformName, strings.Join(allowed, ", "))
+		}
+	}
+
+	for i := 0; i <= 2; i++ {
+		key := fmt.Sprintf("wic%d", i)
+		module := d.Get(key).(string)
+		if module == "" || !d.NewValueKnown(key) {
+			continue
+		}
+		if i >= platform.WICs {
+			return fmt.Errorf("%s: platform %s does not support WIC modules", key, platformName)
+		}
+		if !containsString(platform.WICModules, module) {
+			return fmt.Errorf("%s: module %q is not valid for %s, expected one of %s", key, module, platformName, strings.Join(platform.WICModules, ", "))
+		}
+	}
+	return nil
+}
+
+func resourceGns3DynamipsRouterCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	properties := map[string]interface{}{
+		"platform": d.Get("platform").(string),
+	}
+	for _, key := range dynamipsPropertyKeys {
+		if v, ok := d.GetOk(key); ok {
+			properties[key] = v
+		}
+	}
+
+	computeID, err := placeComputeID(d, meta, "ram")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"name":       d.Get("name").(string),
+		"node_type":  "dynamips",
+		"compute_id": computeID,
+		"properties": properties,
+	}
+	if v, ok := d.GetOk("console"); ok {
+		payload["console"] = v.(int)
+	}
+	if xv, ok := d.GetOkExists("x"); ok {
+		payload["x"] = xv.(int)
+	}
+	if yv, ok := d.GetOkExists("y"); ok {
+		payload["y"] = yv.(int)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Dynamips node data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create Dynamips router: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create Dynamips router, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return fmt.Errorf("failed to decode Dynamips router response: %s", err)
+	}
+	nodeID, ok := created["node_id"].(string)
+	if !ok || nodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+	d.SetId(nodeID)
+
+	// Upload configuration files before the first start
+	if err := uploadNodeFiles(d, host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	if d.Get("auto_idlepc").(bool) && d.Get("idlepc").(string) == "" {
+		if err := applyDynamipsAutoIdlePC(host, projectID, nodeID); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeStatus(host, projectID, nodeID, "stopped", desiredNodeStatus(d, "")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	return resourceGns3DynamipsRouterRead(d, meta)
+}
+
+// applyDynamipsAutoIdlePC asks the compute for an idle-PC value and stores it in the
+// node properties. The auto_idlepc result is preferred; the first idle-PC proposal is
+// used when the compute could not settle on a value.
+func applyDynamipsAutoIdlePC(host, projectID, nodeID string) error {
+	var auto struct {
+		IdlePC string `json:"idlepc"`
+	}
+	if err := getDynamipsIdlePC(host, projectID, nodeID, "auto_idlepc", &auto); err != nil {
+		return err
+	}
+	idlepc := auto.IdlePC
+
+	if idlepc == "" {
+		var proposals []string
+		if err := getDynamipsIdlePC(host, projectID, nodeID, "idlepc_proposals", &proposals); err != nil {
+			return err
+		}
+		if len(proposals) == 0 {
+			return fmt.Errorf("compute found no idle-PC value for Dynamips router %s", nodeID)
+		}
+		idlepc = proposals[0]
+	}
+
+	log.Printf("[INFO] Using idle-PC %s for Dynamips router %s", idlepc, nodeID)
+	return updateNodeProperties(host, projectID, nodeID, map[string]interface{}{"idlepc": idlepc})
+}
+
+func getDynamipsIdlePC(host, projectID, nodeID, endpoint string, out interface{}) error {
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/dynamips/%s", host, projectID, nodeID, endpoint)
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to query %s for Dynamips router %s: %s", endpoint, nodeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to query %s for Dynamips router %s, status: %d, response: %s", endpoint, nodeID, resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %s", endpoint, err)
+	}
+	return nil
+}
+
+func resourceGns3DynamipsRouterRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("compute_id", node["compute_id"])
+	d.Set("status", node["status"])
+	if v, ok := node["console"].(float64); ok {
+		d.Set("console", int(v))
+	}
+	if v, ok := node["x"].(float64); ok {
+		d.Set("x", int(v))
+	}
+	if v, ok := node["y"].(float64); ok {
+		d.Set("y", int(v))
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	if v, ok := props["platform"].(string); ok {
+		d.Set("platform", v)
+	}
+	if v, ok := props["dynamips_id"].(float64); ok {
+		d.Set("dynamips_id", int(v))
+	}
+	setNodeProperties(d, props, dynamipsPropertyKeys)
+
+	return readNodeFiles(d, host, projectID, nodeID, node)
+}
+
+func resourceGns3DynamipsRouterUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	if d.HasChanges("startup_config", "private_config", "files") {
+		if err := uploadNodeFiles(d, host, projectID, nodeID); err != nil {
+			return err
+		}
+	}
+
+	// Only compute when no value is known yet, so later applies reuse it
+	if d.Get("auto_idlepc").(bool) && d.Get("idlepc").(string) == "" {
+		if err := applyDynamipsAutoIdlePC(host, projectID, nodeID); err != nil {
+			return err
+		}
+	}
+
+	oldStatus, _ := d.GetChange("status")
+	currentStatus := oldStatus.(string)
+	desired := desiredNodeStatus(d, "")
+
+	hardwareChanged := false
+	for _, key := range dynamipsPropertyKeys {
+		if d.HasChange(key) {
+			hardwareChanged = true
+		}
+	}
+
+	putPayload := map[string]interface{}{}
+	if hardwareChanged {
+		// Dynamips only accepts hardware changes while the router is stopped
+		if currentStatus != "stopped" {
+			if err := nodeAction(host, projectID, nodeID, "stop"); err != nil {
+				return err
+			}
+			if desired == "" {
+				desired = currentStatus
+			}
+			currentStatus = "stopped"
+		}
+
+		properties := map[string]interface{}{}
+		for _, key := range dynamipsPropertyKeys {
+			if d.HasChange(key) {
+				properties[key] = d.Get(key)
+			}
+		}
+		putPayload["properties"] = properties
+	}
+	if d.HasChange("name") {
+		putPayload["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("console") {
+		if v, ok := d.GetOk("console"); ok {
+			putPayload["console"] = v.(int)
+		}
+	}
+	if d.HasChange("x") {
+		putPayload["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		putPayload["y"] = d.Get("y").(int)
+	}
+
+	if len(putPayload) > 0 {
+		if err := updateNode(host, projectID, nodeID, putPayload); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeStatus(host, projectID, nodeID, currentStatus, desired); err != nil {
+		return err
+	}
+
+	return resourceGns3DynamipsRouterRead(d, meta)
+}
+
+func resourceGns3DynamipsRouterDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for Dynamips router: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete Dynamips router: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete Dynamips router, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3DynamipsRouterImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
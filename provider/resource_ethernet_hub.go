@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// EthernetHub represents a GNS3 Ethernet hub node API request/response.
+type EthernetHub struct {
+	Name       string                 `json:"name"`
+	NodeType   string                 `json:"node_type"`
+	ComputeID  string                 `json:"compute_id,omitempty"`
+	NodeID     string                 `json:"node_id,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	X          int                    `json:"x,omitempty"`
+	Y          int                    `json:"y,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// resourceGns3EthernetHub defines the Terraform resource schema for GNS3 Ethernet hubs.
+func resourceGns3EthernetHub() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3EthernetHubCreate,
+		Read:   resourceGns3EthernetHubRead,
+		Update: resourceGns3EthernetHubUpdate,
+		Delete: resourceGns3EthernetHubDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3EthernetHubImporter,
+		},
+		CustomizeDiff: computePlacementCustomizeDiff(""),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project ID where the hub is deployed.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the hub node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the hub node is running.",
+			},
+			"placement": placementSchema(),
+			"ports": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      8,
+				ValidateFunc: validation.IntBetween(1, 64),
+				Description:  "Number of hub ports.",
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the hub node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the hub node in GNS3 GUI.",
+			},
+			"status": builtinNodeStatusSchema(),
+			"ports_mapping": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Hub ports as reported by GNS3.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port_number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"hub_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hub node's ID assigned by GNS3.",
+			},
+		},
+	}
+}
+
+// hubPortsMapping builds the GNS3 ports_mapping for a hub with the given port count.
+func hubPortsMapping(ports int) []map[string]interface{} {
+	mapping := make([]map[string]interface{}, 0, ports)
+	for i := 0; i < ports; i++ {
+		mapping = append(mapping, map[string]interface{}{
+			"name":        fmt.Sprintf("Ethernet%d", i),
+			"port_number": i,
+		})
+	}
+	return mapping
+}
+
+func resourceGns3EthernetHubCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
+
+	hub := EthernetHub{
+		Name:      d.Get("name").(string),
+		NodeType:  "ethernet_hub",
+		ComputeID: computeID,
+		X:         d.Get("x").(int),
+		Y:         d.Get("y").(int),
+		Properties: map[string]interface{}{
+			"ports_mapping": hubPortsMapping(d.Get("ports").(int)),
+		},
+	}
+
+	data, err := json.Marshal(hub)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hub data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("error creating GNS3 hub: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errResp map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("failed to create hub, status code: %d, error: %v", resp.StatusCode, errResp)
+	}
+
+	var createdHub EthernetHub
+	if err := json.NewDecoder(resp.Body).Decode(&createdHub); err != nil {
+		return fmt.Errorf("failed to decode hub response: %s", err)
+	}
+
+	if createdHub.NodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+
+	d.SetId(createdHub.NodeID)
+	d.Set("hub_id", createdHub.NodeID)
+
+	return resourceGns3EthernetHubRead(d, meta)
+}
+
+func resourceGns3EthernetHubUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	hubID := d.Id()
+
+	updateData := map[string]interface{}{}
+
+	if d.HasChange("name") {
+		updateData["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("x") {
+		updateData["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		updateData["y"] = d.Get("y").(int)
+	}
+	if d.HasChange("ports") {
+		updateData["properties"] = map[string]interface{}{
+			"ports_mapping": hubPortsMapping(d.Get("ports").(int)),
+		}
+	}
+
+	if len(updateData) > 0 {
+		if err := updateNode(host, projectID, hubID, updateData); err != nil {
+			return err
+		}
+	}
+
+	return resourceGns3EthernetHubRead(d, meta)
+}
+
+func resourceGns3EthernetHubRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("status", node["status"])
+	d.Set("hub_id", nodeID)
+
+	props, _ := node["properties"].(map[string]interface{})
+	mapping, _ := props["ports_mapping"].([]interface{})
+	ports := make([]interface{}, 0, len(mapping))
+	for _, raw := range mapping {
+		port, _ := raw.(map[string]interface{})
+		number, _ := port["port_number"].(float64)
+		ports = append(ports, map[string]interface{}{
+			"name":        port["name"],
+			"port_number": int(number),
+		})
+	}
+	d.Set("ports", len(ports))
+	if err := d.Set("ports_mapping", ports); err != nil {
+		return fmt.Errorf("failed to set ports_mapping: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGns3EthernetHubDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for hub: %s", err)
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete hub: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete hub, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3EthernetHubImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// FrameRelaySwitch represents a GNS3 Frame Relay switch node API request/response.
+type FrameRelaySwitch struct {
+	Name       string                 `json:"name"`
+	NodeType   string                 `json:"node_type"`
+	ComputeID  string                 `json:"compute_id,omitempty"`
+	NodeID     string                 `json:"node_id,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	X          int                    `json:"x,omitempty"`
+	Y          int                    `json:"y,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+var frameRelayMappingPattern = regexp.MustCompile(`^\d+:\d+$`)
+
+// validateSwitchMappings checks that every key and value of a mappings map matches
+// pattern and that both sides of a mapping have the same number of fields.
+func validateSwitchMappings(pattern *regexp.Regexp, format string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		var errs []error
+		for src, raw := range i.(map[string]interface{}) {
+			dst, _ := raw.(string)
+			if !pattern.MatchString(src) || !pattern.MatchString(dst) {
+				errs = append(errs, fmt.Errorf("%s: mapping %q = %q must use the format %s", k, src, dst, format))
+				continue
+			}
+			if strings.Count(src, ":") != strings.Count(dst, ":") {
+				errs = append(errs, fmt.Errorf("%s: mapping %q = %q must have the same format on both sides", k, src, dst))
+			}
+		}
+		return nil, errs
+	}
+}
+
+// readSwitchMappings converts the mappings property of a node into a string map.
+func readSwitchMappings(node map[string]interface{}) map[string]interface{} {
+	mappings := map[string]interface{}{}
+	props, _ := node["properties"].(map[string]interface{})
+	if raw, ok := props["mappings"].(map[string]interface{}); ok {
+		for src, dst := range raw {
+			mappings[src] = fmt.Sprintf("%v", dst)
+		}
+	}
+	return mappings
+}
+
+// resourceGns3FrameRelaySwitch defines the Terraform resource schema for GNS3 Frame Relay switches.
+func resourceGns3FrameRelaySwitch() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3FrameRelaySwitchCreate,
+		Read:   resourceGns3FrameRelaySwitchRead,
+		Update: resourceGns3FrameRelaySwitchUpdate,
+		Delete: resourceGns3FrameRelaySwitchDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3FrameRelaySwitchImporter,
+		},
+		CustomizeDiff: computePlacementCustomizeDiff(""),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project ID where the Frame Relay switch is deployed.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the Frame Relay switch node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the Frame Relay switch node is running.",
+			},
+			"placement": placementSchema(),
+			"mappings": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ValidateFunc: validateSwitchMappings(frameRelayMappingPattern, "<port>:<dlci>"),
+				Description:  "DLCI mappings in the form \"<port>:<dlci>\" = \"<port>:<dlci>\".",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the Frame Relay switch node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the Frame Relay switch node in GNS3 GUI.",
+			},
+			"status": builtinNodeStatusSchema(),
+			"frame_relay_switch_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Frame Relay switch node's ID assigned by GNS3.",
+			},
+		},
+	}
+}
+
+func resourceGns3FrameRelaySwitchCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
+
+	sw := FrameRelaySwitch{
+		Name:      d.Get("name").(string),
+		NodeType:  "frame_relay_switch",
+		ComputeID: computeID,
+		X:         d.Get("x").(int),
+		Y:         d.Get("y").(int),
+		Properties: map[string]interface{}{
+			"mappings": d.Get("mappings").(map[string]interface{}),
+		},
+	}
+
+	data, err := json.Marshal(sw)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Frame Relay switch data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("error creating GNS3 Frame Relay switch: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errResp map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("failed to create Frame Relay switch, status code: %d, error: %v", resp.StatusCode, errResp)
+	}
+
+	var createdSwitch FrameRelaySwitch
+	if err := json.NewDecoder(resp.Body).Decode(&createdSwitch); err != nil {
+		return fmt.Errorf("failed to decode Frame Relay switch response: %s", err)
+	}
+
+	if createdSwitch.NodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+
+	d.SetId(createdSwitch.NodeID)
+	d.Set("frame_relay_switch_id", createdSwitch.NodeID)
+
+	return resourceGns3FrameRelaySwitchRead(d, meta)
+}
+
+func resourceGns3FrameRelaySwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	switchID := d.Id()
+
+	updateData := map[string]interface{}{}
+
+	if d.HasChange("name") {
+		updateData["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("x") {
+		updateData["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		updateData["y"] = d.Get("y").(int)
+	}
+	if d.HasChange("mappings") {
+		updateData["properties"] = map[string]interface{}{
+			"mappings": d.Get("mappings").(map[string]interface{}),
+		}
+	}
+
+	if len(updateData) > 0 {
+		if err := updateNode(host, projectID, switchID, updateData); err != nil {
+			return err
+		}
+	}
+
+	return resourceGns3FrameRelaySwitchRead(d, meta)
+}
+
+func resourceGns3FrameRelaySwitchRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("status", node["status"])
+	d.Set("frame_relay_switch_id", nodeID)
+	if err := d.Set("mappings", readSwitchMappings(node)); err != nil {
+		return fmt.Errorf("failed to set mappings: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGns3FrameRelaySwitchDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for Frame Relay switch: %s", err)
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete Frame Relay switch: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete Frame Relay switch, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3FrameRelaySwitchImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,345 @@
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// imageTypes are the emulators with an image store on the compute.
+var imageTypes = []string{"qemu", "iou", "dynamips"}
+
+// listImages returns the images a compute holds for the given emulator, through the controller.
+func listImages(host, computeID, imageType string) ([]map[string]interface{}, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v2/computes/%s/%s/images", host, computeID, imageType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s images: %s", imageType, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list %s images, status code: %d, response: %s", imageType, resp.StatusCode, string(body))
+	}
+
+	var images []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&images); err != nil {
+		return nil, fmt.Errorf("failed to decode %s image list: %s", imageType, err)
+	}
+	return images, nil
+}
+
+// findImage looks up an image by filename. found is false when the compute doesn't have it.
+func findImage(host, computeID, imageType, filename string) (map[string]interface{}, bool, error) {
+	images, err := listImages(host, computeID, imageType)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, image := range images {
+		if name, _ := image["filename"].(string); name == filename {
+			return image, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// uploadImage streams r to the compute's image store through the controller, which
+// authenticates to the compute, and checks the MD5 the compute reports afterwards
+// against the bytes that were sent. It returns that checksum.
+func uploadImage(host, computeID, imageType, filename string, r io.Reader) (string, error) {
+	hasher := md5.New()
+	body := io.TeeReader(r, hasher)
+
+	apiURL := fmt.Sprintf("%s/v2/computes/%s/%s/images/%s", host, computeID, imageType, url.PathEscape(filename))
+	req, err := http.NewRequest("POST", apiURL, ioutil.NopCloser(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request for image %s: %s", filename, err)
+	}
+	// Unknown length makes the client send the file in chunks instead of buffering it
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload image %s: %s", filename, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusCreated {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to upload image %s, status code: %d, response: %s", filename, resp.StatusCode, string(msg))
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	image, found, err := findImage(host, computeID, imageType, filename)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("image %s not listed by the compute after upload", filename)
+	}
+	if remote, _ := image["md5sum"].(string); remote != "" && !strings.EqualFold(remote, sum) {
+		return "", fmt.Errorf("MD5 mismatch after uploading image %s: sent %s, compute has %s", filename, sum, remote)
+	}
+	return sum, nil
+}
+
+// fileMD5 returns the hex MD5 checksum of a local file.
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hasher := md5.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// resourceGns3Image defines the Terraform resource schema for compute images.
+func resourceGns3Image() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3ImageCreate,
+		Read:   resourceGns3ImageRead,
+		Update: resourceGns3ImageCreate,
+		Delete: resourceGns3ImageDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3ImageImporter,
+		},
+		CustomizeDiff: resourceGns3ImageCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"compute_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "local",
+				Description: "Compute to upload the image to. The upload goes through the controller.",
+			},
+			"image_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(imageTypes, false),
+				Description:  "Emulator the image is for: qemu, iou or dynamips.",
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of the local file to upload. Not needed when importing an image already on the compute.",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Name of the image on the compute. Defaults to the base name of source.",
+			},
+			"md5sum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "MD5 checksum of the image on the compute.",
+			},
+			"filesize": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the image in bytes.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Path of the image as reported by the compute.",
+			},
+			"source_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of source when it was last hashed.",
+			},
+			"source_modified": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Modification time of source when it was last hashed.",
+			},
+			"source_md5": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "MD5 checksum of source when it was last hashed.",
+			},
+		},
+	}
+}
+
+// sourceFingerprint returns the size and modification time of a local file, used to
+// skip hashing multi-GB images when they have not changed.
+func sourceFingerprint(path string) (int, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, "", err
+	}
+	return int(info.Size()), info.ModTime().UTC().Format(time.RFC3339Nano), nil
+}
+
+// resourceGns3ImageCustomizeDiff plans a re-upload when the local file no longer matches the compute.
+// The file is only hashed again when its size or modification time changed.
+func resourceGns3ImageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("source") {
+		return nil
+	}
+	source := d.Get("source").(string)
+	if source == "" {
+		return nil
+	}
+	if d.GetRawConfig().GetAttr("filename").IsNull() {
+		if err := d.SetNew("filename", filepath.Base(source)); err != nil {
+			return err
+		}
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	size, modified, err := sourceFingerprint(source)
+	if err != nil {
+		return fmt.Errorf("failed to read image source %s: %s", source, err)
+	}
+	sum := d.Get("source_md5").(string)
+	if sum == "" || size != d.Get("source_size").(int) || modified != d.Get("source_modified").(string) {
+		if sum, err = fileMD5(source); err != nil {
+			return fmt.Errorf("failed to read image source %s: %s", source, err)
+		}
+		for key, value := range map[string]interface{}{"source_size": size, "source_modified": modified, "source_md5": sum} {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	if !strings.EqualFold(sum, d.Get("md5sum").(string)) {
+		return d.SetNew("md5sum", sum)
+	}
+	return nil
+}
+
+func resourceGns3ImageCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	computeID := d.Get("compute_id").(string)
+	imageType := d.Get("image_type").(string)
+	source := d.Get("source").(string)
+	if source == "" {
+		return fmt.Errorf("source is required to upload an image")
+	}
+	filename := d.Get("filename").(string)
+	if filename == "" {
+		filename = filepath.Base(source)
+	}
+
+	size, modified, err := sourceFingerprint(source)
+	if err != nil {
+		return fmt.Errorf("failed to read image source %s: %s", source, err)
+	}
+	sum, err := fileMD5(source)
+	if err != nil {
+		return fmt.Errorf("failed to read image source %s: %s", source, err)
+	}
+
+	// Skip the transfer when the compute already has this exact file
+	image, found, err := findImage(host, computeID, imageType, filename)
+	if err != nil {
+		return err
+	}
+	remote, _ := image["md5sum"].(string)
+	if !found || !strings.EqualFold(remote, sum) {
+		f, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("failed to open image source %s: %s", source, err)
+		}
+		defer f.Close()
+
+		uploaded, err := uploadImage(host, computeID, imageType, filename, f)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(uploaded, sum) {
+			return fmt.Errorf("image source %s changed during upload", source)
+		}
+	}
+
+	if computeID == "local" {
+		d.SetId(fmt.Sprintf("%s/%s", imageType, filename))
+	} else {
+		d.SetId(fmt.Sprintf("%s/%s/%s", computeID, imageType, filename))
+	}
+	d.Set("filename", filename)
+	d.Set("source_size", size)
+	d.Set("source_modified", modified)
+	d.Set("source_md5", sum)
+
+	return resourceGns3ImageRead(d, meta)
+}
+
+func resourceGns3ImageRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	imageType := d.Get("image_type").(string)
+	filename := d.Get("filename").(string)
+
+	image, found, err := findImage(config.Host, d.Get("compute_id").(string), imageType, filename)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("md5sum", image["md5sum"])
+	d.Set("path", image["path"])
+	if v, ok := image["filesize"].(float64); ok {
+		d.Set("filesize", int(v))
+	}
+	return nil
+}
+
+// resourceGns3ImageDelete only removes the image from state; the GNS3 v2 compute API
+// has no endpoint for deleting images, and other templates may still use the file.
+func resourceGns3ImageDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3ImageImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	computeID := "local"
+	parts := strings.SplitN(raw, "/", 2)
+	if len(parts) == 2 && !containsString(imageTypes, parts[0]) {
+		computeID = parts[0]
+		parts = strings.SplitN(parts[1], "/", 2)
+	}
+	if len(parts) != 2 || !containsString(imageTypes, parts[0]) {
+		return nil, fmt.Errorf("invalid import ID %q — expected format [<compute_id>/]<image_type>/<filename>", raw)
+	}
+
+	d.Set("compute_id", computeID)
+	d.Set("image_type", parts[0])
+	d.Set("filename", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
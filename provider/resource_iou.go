@@ -0,0 +1,426 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// iouPropertyKeys are the node properties managed one-to-one by the resource.
+var iouPropertyKeys = []string{
+	"path", "ethernet_adapters", "serial_adapters", "ram", "nvram",
+	"l1_keepalives", "use_default_iou_values", "application_id",
+}
+
+// iouStartupConfigFile is where IOU nodes keep their startup configuration.
+const iouStartupConfigFile = "startup-config.cfg"
+
+// resourceGns3IOUNode defines the Terraform resource schema for IOU (IOS on Unix) nodes.
+func resourceGns3IOUNode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3IOUNodeCreate,
+		Read:   resourceGns3IOUNodeRead,
+		Update: resourceGns3IOUNodeUpdate,
+		Delete: resourceGns3IOUNodeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3IOUNodeImporter,
+		},
+		CustomizeDiff: customdiff.All(
+			computePlacementCustomizeDiff("ram"),
+			waitForCustomizeDiff(""),
+		),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The project ID where the IOU node is created.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the IOU node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the IOU node runs. IOU requires a Linux compute.",
+			},
+			"placement": placementSchema(),
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "IOU image file name on the compute.",
+			},
+			"ethernet_adapters": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(0, 16),
+				Description:  "Number of Ethernet adapters (4 interfaces each).",
+			},
+			"serial_adapters": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntBetween(0, 16),
+				Description:  "Number of serial adapters (4 interfaces each).",
+			},
+			"ram": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     256,
+				Description: "RAM in MB.",
+			},
+			"nvram": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     128,
+				Description: "NVRAM in KB.",
+			},
+			"l1_keepalives": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Enable layer 1 keepalive messages.",
+			},
+			"use_default_iou_values": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Use the default IOU RAM and NVRAM values instead of ram and nvram.",
+			},
+			"application_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "IOU application ID. Allocated by GNS3 when not set.",
+			},
+			"startup_config_content": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Startup configuration loaded when the node boots.",
+			},
+			"console": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Console TCP port. Allocated by GNS3 when not set.",
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the IOU node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the IOU node in GNS3 GUI.",
+			},
+			"status":   nodeStatusSchema(),
+			"wait_for": waitForSchema(),
+		},
+	}
+}
+
+// pushIOULicense writes the provider's iourc content to the controller, which
+// distributes it to the computes. Nothing is sent when the content already matches.
+func pushIOULicense(config *ProviderConfig) error {
+	if config.IOURCContent == "" {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/v2/iou_license", config.Host)
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to read IOU license settings: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read IOU license settings, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	license := map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&license); err != nil {
+		return fmt.Errorf("failed to decode IOU license settings: %s", err)
+	}
+	if license["iourc_content"] == config.IOURCContent {
+		return nil
+	}
+	license["iourc_content"] = config.IOURCContent
+
+	data, err := json.Marshal(license)
+	if err != nil {
+		return fmt.Errorf("failed to marshal IOU license settings: %s", err)
+	}
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create IOU license request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	putResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update IOU license: %s", err)
+	}
+	defer putResp.Body.Close()
+
+	if putResp.StatusCode != http.StatusOK && putResp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(putResp.Body)
+		return fmt.Errorf("failed to update IOU license, status: %d, response: %s", putResp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func resourceGns3IOUNodeCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	if err := pushIOULicense(config); err != nil {
+		return err
+	}
+
+	// Booleans are sent even when false; application_id is left to GNS3 unless set
+	properties := map[string]interface{}{}
+	for _, key := range iouPropertyKeys {
+		properties[key] = d.Get(key)
+	}
+	if _, ok := d.GetOk("application_id"); !ok {
+		delete(properties, "application_id")
+	}
+	if v, ok := d.GetOk("startup_config_content"); ok {
+		properties["startup_config_content"] = v.(string)
+	}
+
+	computeID, err := placeComputeID(d, meta, "ram")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"name":       d.Get("name").(string),
+		"node_type":  "iou",
+		"compute_id": computeID,
+		"properties": properties,
+	}
+	if v, ok := d.GetOk("console"); ok {
+		payload["console"] = v.(int)
+	}
+	if xv, ok := d.GetOkExists("x"); ok {
+		payload["x"] = xv.(int)
+	}
+	if yv, ok := d.GetOkExists("y"); ok {
+		payload["y"] = yv.(int)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal IOU node data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create IOU node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create IOU node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return fmt.Errorf("failed to decode IOU node response: %s", err)
+	}
+	nodeID, ok := created["node_id"].(string)
+	if !ok || nodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+	d.SetId(nodeID)
+
+	if err := setNodeStatus(host, projectID, nodeID, "stopped", desiredNodeStatus(d, "")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	return resourceGns3IOUNodeRead(d, meta)
+}
+
+func resourceGns3IOUNodeRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("compute_id", node["compute_id"])
+	d.Set("status", node["status"])
+	if v, ok := node["console"].(float64); ok {
+		d.Set("console", int(v))
+	}
+	if v, ok := node["x"].(float64); ok {
+		d.Set("x", int(v))
+	}
+	if v, ok := node["y"].(float64); ok {
+		d.Set("y", int(v))
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	setNodeProperties(d, props, iouPropertyKeys)
+
+	// The startup config is only tracked when managed by Terraform
+	if _, ok := d.GetOk("startup_config_content"); ok {
+		content, _, err := readNodeFile(host, projectID, nodeID, iouStartupConfigFile)
+		if err != nil {
+			return err
+		}
+		d.Set("startup_config_content", content)
+	}
+
+	return nil
+}
+
+func resourceGns3IOUNodeUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	if err := pushIOULicense(config); err != nil {
+		return err
+	}
+
+	if d.HasChange("startup_config_content") {
+		if err := uploadNodeFile(host, projectID, nodeID, iouStartupConfigFile, d.Get("startup_config_content").(string)); err != nil {
+			return err
+		}
+	}
+
+	oldStatus, _ := d.GetChange("status")
+	currentStatus := oldStatus.(string)
+	desired := desiredNodeStatus(d, "")
+
+	putPayload := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	for _, key := range iouPropertyKeys {
+		if d.HasChange(key) {
+			properties[key] = d.Get(key)
+		}
+	}
+	if len(properties) > 0 {
+		// Adapter and memory changes only apply to a stopped node
+		if currentStatus != "stopped" {
+			if err := nodeAction(host, projectID, nodeID, "stop"); err != nil {
+				return err
+			}
+			if desired == "" {
+				desired = currentStatus
+			}
+			currentStatus = "stopped"
+		}
+		putPayload["properties"] = properties
+	}
+	if d.HasChange("name") {
+		putPayload["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("console") {
+		if v, ok := d.GetOk("console"); ok {
+			putPayload["console"] = v.(int)
+		}
+	}
+	if d.HasChange("x") {
+		putPayload["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		putPayload["y"] = d.Get("y").(int)
+	}
+
+	if len(putPayload) > 0 {
+		if err := updateNode(host, projectID, nodeID, putPayload); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeStatus(host, projectID, nodeID, currentStatus, desired); err != nil {
+		return err
+	}
+
+	return resourceGns3IOUNodeRead(d, meta)
+}
+
+func resourceGns3IOUNodeDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for IOU node: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete IOU node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete IOU node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3IOUNodeImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -22,8 +23,10 @@ type LinkNode struct {
 
 // Link represents a GNS3 link between nodes.
 type Link struct {
-	LinkID string     `json:"link_id,omitempty"`
-	Nodes  []LinkNode `json:"nodes"`
+	LinkID    string     `json:"link_id,omitempty"`
+	Nodes     []LinkNode `json:"nodes"`
+	LinkType  string     `json:"link_type,omitempty"`
+	Capturing bool       `json:"capturing,omitempty"`
 }
 
 func waitForNode(host, projectID, nodeID string) error {
@@ -52,6 +55,101 @@ func waitForNode(host, projectID, nodeID string) error {
 	return fmt.Errorf("node %s not found in controller after polling", nodeID)
 }
 
+// nodeComputeID returns the compute a node runs on.
+func nodeComputeID(host, projectID, nodeID string) (string, error) {
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("node %s not found", nodeID)
+	}
+	computeID, _ := node["compute_id"].(string)
+	return computeID, nil
+}
+
+// interfaceNetworks returns the IPv4 networks of a compute's interfaces.
+func interfaceNetworks(interfaces []map[string]interface{}) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, iface := range interfaces {
+		address, _ := iface["ip_address"].(string)
+		netmask, _ := iface["netmask"].(string)
+		ip := net.ParseIP(address).To4()
+		mask := net.ParseIP(netmask).To4()
+		if ip == nil || mask == nil || ip.IsLoopback() {
+			continue
+		}
+		networks = append(networks, &net.IPNet{IP: ip.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)})
+	}
+	return networks
+}
+
+// checkComputeConnectivity verifies that the controller can set up a UDP tunnel
+// between two computes. Both must be connected. Like the controller, it trusts
+// routable compute addresses; when a compute listens on a loopback or wildcard
+// address the computes must share an IPv4 subnet instead.
+func checkComputeConnectivity(host, computeA, computeB string) error {
+	routable := true
+	for _, computeID := range []string{computeA, computeB} {
+		compute, found, err := getCompute(host, computeID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("compute %s not found", computeID)
+		}
+		if connected, _ := compute["connected"].(bool); !connected {
+			return fmt.Errorf("compute %s is not connected to the controller", computeID)
+		}
+		if address, _ := compute["host"].(string); address == "0.0.0.0" || address == "127.0.0.1" || address == "localhost" {
+			routable = false
+		}
+	}
+	if routable {
+		return nil
+	}
+
+	networks := make([][]*net.IPNet, 0, 2)
+	for _, computeID := range []string{computeA, computeB} {
+		interfaces, err := computeInterfaces(host, computeID)
+		if err != nil {
+			return err
+		}
+		networks = append(networks, interfaceNetworks(interfaces))
+	}
+
+	for _, a := range networks[0] {
+		for _, b := range networks[1] {
+			if a.Contains(b.IP) || b.Contains(a.IP) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("computes %s and %s share no IPv4 subnet, so the controller cannot set up a UDP tunnel between them", computeA, computeB)
+}
+
+// checkLinkComputes looks up the computes of the two nodes and, when they differ,
+// checks that the controller can tunnel between them. Links across computes are UDP
+// tunnels, so checking first names the cause instead of surfacing a generic link
+// error. It returns the text to add to link errors, empty for a same-compute link.
+func checkLinkComputes(host, projectID, nodeAID, nodeBID string) (string, error) {
+	computeA, err := nodeComputeID(host, projectID, nodeAID)
+	if err != nil {
+		return "", err
+	}
+	computeB, err := nodeComputeID(host, projectID, nodeBID)
+	if err != nil {
+		return "", err
+	}
+	if computeA == computeB {
+		return "", nil
+	}
+	if err := checkComputeConnectivity(host, computeA, computeB); err != nil {
+		return "", fmt.Errorf("cannot link nodes on computes %s and %s: %s", computeA, computeB, err)
+	}
+	return fmt.Sprintf(" (UDP tunnel between computes %s and %s)", computeA, computeB), nil
+}
+
 // resourceGns3Link defines the GNS3 link resource schema.
 func resourceGns3Link() *schema.Resource {
 	return &schema.Resource{
@@ -104,6 +202,31 @@ func resourceGns3Link() *schema.Resource {
 				Computed:    true,
 				Description: "The unique ID of the link returned by the GNS3 API.",
 			},
+			"link_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the link, ethernet or serial.",
+			},
+			"capturing": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether a packet capture is running on the link.",
+			},
+			"compute_a_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compute running the first node.",
+			},
+			"compute_b_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Compute running the second node.",
+			},
+			"cross_compute": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the nodes run on different computes, so the link is a UDP tunnel between them.",
+			},
 		},
 	}
 }
@@ -126,6 +249,11 @@ func resourceGns3LinkCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("node B not found: %s", err)
 	}
 
+	tunnel, err := checkLinkComputes(host, projectID, nodeAID, nodeBID)
+	if err != nil {
+		return err
+	}
+
 	// Build the link payload.
 	link := Link{
 		Nodes: []LinkNode{
@@ -157,9 +285,9 @@ func resourceGns3LinkCreate(d *schema.ResourceData, meta interface{}) error {
 	if resp.StatusCode != http.StatusCreated {
 		var errorResponse map[string]interface{}
 		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-			return fmt.Errorf("failed to create link, status code: %d", resp.StatusCode)
+			return fmt.Errorf("failed to create link%s, status code: %d", tunnel, resp.StatusCode)
 		}
-		return fmt.Errorf("failed to create link, status code: %d, error: %v", resp.StatusCode, errorResponse)
+		return fmt.Errorf("failed to create link%s, status code: %d, error: %v", tunnel, resp.StatusCode, errorResponse)
 	}
 
 	var createdLink Link
@@ -169,7 +297,7 @@ func resourceGns3LinkCreate(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(createdLink.LinkID)
 	d.Set("link_id", createdLink.LinkID)
-	return nil
+	return resourceGns3LinkRead(d, meta)
 }
 
 func resourceGns3LinkRead(d *schema.ResourceData, meta interface{}) error {
@@ -196,7 +324,35 @@ func resourceGns3LinkRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("failed to read link, status code: %d, response: %s", resp.StatusCode, string(body))
 	}
 
-	// Optionally parse and update fields if needed
+	var link Link
+	if err := json.NewDecoder(resp.Body).Decode(&link); err != nil {
+		return fmt.Errorf("failed to decode link: %s", err)
+	}
+
+	d.Set("link_id", linkID)
+	d.Set("link_type", link.LinkType)
+	d.Set("capturing", link.Capturing)
+	if len(link.Nodes) != 2 {
+		return nil
+	}
+	d.Set("node_a_id", link.Nodes[0].NodeID)
+	d.Set("node_a_adapter", link.Nodes[0].AdapterNumber)
+	d.Set("node_a_port", link.Nodes[0].PortNumber)
+	d.Set("node_b_id", link.Nodes[1].NodeID)
+	d.Set("node_b_adapter", link.Nodes[1].AdapterNumber)
+	d.Set("node_b_port", link.Nodes[1].PortNumber)
+
+	computeA, err := nodeComputeID(host, projectID, link.Nodes[0].NodeID)
+	if err != nil {
+		return err
+	}
+	computeB, err := nodeComputeID(host, projectID, link.Nodes[1].NodeID)
+	if err != nil {
+		return err
+	}
+	d.Set("compute_a_id", computeA)
+	d.Set("compute_b_id", computeB)
+	d.Set("cross_compute", computeA != computeB)
 	return nil
 }
 
@@ -206,17 +362,25 @@ func resourceGns3LinkUpdate(d *schema.ResourceData, meta interface{}) error {
 	host := config.Host
 	projectID := d.Get("project_id").(string)
 	linkID := d.Id()
+	nodeAID := d.Get("node_a_id").(string)
+	nodeBID := d.Get("node_b_id").(string)
+
+	// Moving an endpoint can turn the link into a tunnel between computes
+	tunnel, err := checkLinkComputes(host, projectID, nodeAID, nodeBID)
+	if err != nil {
+		return err
+	}
 
 	// Build the update payload with the updated attributes.
 	link := Link{
 		Nodes: []LinkNode{
 			{
-				NodeID:        d.Get("node_a_id").(string),
+				NodeID:        nodeAID,
 				AdapterNumber: d.Get("node_a_adapter").(int),
 				PortNumber:    d.Get("node_a_port").(int),
 			},
 			{
-				NodeID:        d.Get("node_b_id").(string),
+				NodeID:        nodeBID,
 				AdapterNumber: d.Get("node_b_adapter").(int),
 				PortNumber:    d.Get("node_b_port").(int),
 			},
@@ -245,7 +409,7 @@ func resourceGns3LinkUpdate(d *schema.ResourceData, meta interface{}) error {
 	if resp.StatusCode != http.StatusOK {
 		var errorResponse map[string]interface{}
 		_ = json.NewDecoder(resp.Body).Decode(&errorResponse)
-		return fmt.Errorf("failed to update link, status code: %d, error: %v", resp.StatusCode, errorResponse)
+		return fmt.Errorf("failed to update link%s, status code: %d, error: %v", tunnel, resp.StatusCode, errorResponse)
 	}
 
 	// Optionally re-read the resource state.
@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Nat represents a GNS3 NAT node API request/response.
+type Nat struct {
+	Name      string `json:"name"`
+	NodeType  string `json:"node_type"`
+	ComputeID string `json:"compute_id,omitempty"`
+	NodeID    string `json:"node_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	X         int    `json:"x,omitempty"`
+	Y         int    `json:"y,omitempty"`
+}
+
+// resourceGns3Nat defines the Terraform resource schema for GNS3 NAT nodes.
+func resourceGns3Nat() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3NatCreate,
+		Read:   resourceGns3NatRead,
+		Update: resourceGns3NatUpdate,
+		Delete: resourceGns3NatDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3NatImporter,
+		},
+		CustomizeDiff: computePlacementCustomizeDiff(""),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The project ID where the NAT node is deployed.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the NAT node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the NAT node is running.",
+			},
+			"placement": placementSchema(),
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the NAT node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the NAT node in GNS3 GUI.",
+			},
+			"status": builtinNodeStatusSchema(),
+			"ports_mapping": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "NAT ports and the host interfaces they are bridged to, as reported by GNS3.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port_number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"interface": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"nat_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The NAT node's ID assigned by GNS3.",
+			},
+		},
+	}
+}
+
+func resourceGns3NatCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
+
+	nat := Nat{
+		Name:      d.Get("name").(string),
+		NodeType:  "nat",
+		ComputeID: computeID,
+		X:         d.Get("x").(int),
+		Y:         d.Get("y").(int),
+	}
+
+	data, err := json.Marshal(nat)
+	if err != nil {
+		return fmt.Errorf("failed to marshal NAT node data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("error creating GNS3 NAT node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errResp map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("failed to create NAT node, status code: %d, error: %v", resp.StatusCode, errResp)
+	}
+
+	var createdNat Nat
+	if err := json.NewDecoder(resp.Body).Decode(&createdNat); err != nil {
+		return fmt.Errorf("failed to decode NAT node response: %s", err)
+	}
+
+	if createdNat.NodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+
+	d.SetId(createdNat.NodeID)
+	d.Set("nat_id", createdNat.NodeID)
+
+	return resourceGns3NatRead(d, meta)
+}
+
+func resourceGns3NatUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	natID := d.Id()
+
+	updateData := map[string]interface{}{}
+
+	if d.HasChange("name") {
+		updateData["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("x") {
+		updateData["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		updateData["y"] = d.Get("y").(int)
+	}
+
+	if len(updateData) > 0 {
+		if err := updateNode(host, projectID, natID, updateData); err != nil {
+			return err
+		}
+	}
+
+	return resourceGns3NatRead(d, meta)
+}
+
+func resourceGns3NatRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("status", node["status"])
+	d.Set("nat_id", nodeID)
+
+	props, _ := node["properties"].(map[string]interface{})
+	mapping, _ := props["ports_mapping"].([]interface{})
+	ports := make([]interface{}, 0, len(mapping))
+	for _, raw := range mapping {
+		port, _ := raw.(map[string]interface{})
+		number, _ := port["port_number"].(float64)
+		ports = append(ports, map[string]interface{}{
+			"name":        port["name"],
+			"port_number": int(number),
+			"interface":   port["interface"],
+			"type":        port["type"],
+		})
+	}
+	if err := d.Set("ports_mapping", ports); err != nil {
+		return fmt.Errorf("failed to set ports_mapping: %s", err)
+	}
+
+	return nil
+}
+
+func resourceGns3NatDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for NAT node: %s", err)
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete NAT node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete NAT node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3NatImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
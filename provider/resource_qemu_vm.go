@@ -9,9 +9,110 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// qemuPropertyKeys are the QEMU node properties managed one-to-one by the resource.
+var qemuPropertyKeys = []string{
+	"adapter_type", "adapters", "bios_image", "cdrom_image", "cpus", "ram", "mac_address", "options", "platform",
+	"hda_disk_image", "hda_disk_interface", "hdb_disk_image", "hdb_disk_interface",
+	"hdc_disk_image", "hdc_disk_interface", "hdd_disk_image", "hdd_disk_interface",
+	"boot_priority", "kernel_image", "initrd", "kernel_command_line",
+	"cpu_throttling", "process_priority", "on_close",
+	"linked_clone", "legacy_networking", "replicate_network_connection_state",
+}
+
+// qemuDiskInterfaces are the disk buses accepted for hda-hdd.
+var qemuDiskInterfaces = []string{"ide", "sata", "scsi", "virtio"}
+
+// qemuProperties collects the QEMU properties from the resource data. Empty strings
+// are left out so GNS3 keeps its own defaults for them.
+func qemuProperties(d *schema.ResourceData) map[string]interface{} {
+	props := map[string]interface{}{}
+	for _, key := range qemuPropertyKeys {
+		v := d.Get(key)
+		if str, ok := v.(string); ok && str == "" {
+			continue
+		}
+		props[key] = v
+	}
+	return props
+}
+
+// qemuCustomAdapters returns the custom_adapters list to send to GNS3, built from the
+// adapter blocks when present, otherwise from the custom_adapters JSON.
+func qemuCustomAdapters(d *schema.ResourceData) ([]interface{}, error) {
+	adapters := []interface{}{}
+	if blocks := d.Get("adapter").([]interface{}); len(blocks) > 0 {
+		for _, raw := range blocks {
+			block := raw.(map[string]interface{})
+			adapter := map[string]interface{}{
+				"adapter_number": block["adapter_number"].(int),
+			}
+			for _, key := range []string{"adapter_type", "mac_address", "port_name"} {
+				if v := block[key].(string); v != "" {
+					adapter[key] = v
+				}
+			}
+			adapters = append(adapters, adapter)
+		}
+		return adapters, nil
+	}
+
+	if d.GetRawConfig().GetAttr("custom_adapters").IsNull() {
+		return adapters, nil
+	}
+	if err := json.Unmarshal([]byte(d.Get("custom_adapters").(string)), &adapters); err != nil {
+		return nil, fmt.Errorf("failed to parse custom_adapters: %s", err)
+	}
+	return adapters, nil
+}
+
+// resourceGns3QemuCustomizeDiff checks adapter blocks against the adapter count.
+func resourceGns3QemuCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	count := d.Get("adapters").(int)
+	seen := map[int]bool{}
+	for _, raw := range d.Get("adapter").([]interface{}) {
+		block, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		number := block["adapter_number"].(int)
+		if d.NewValueKnown("adapters") && number >= count {
+			return fmt.Errorf("adapter %d is out of range: the node has %d adapters (0-%d)", number, count, count-1)
+		}
+		if seen[number] {
+			return fmt.Errorf("adapter %d is declared more than once", number)
+		}
+		seen[number] = true
+	}
+
+	if d.Id() != "" && d.HasChange("adapter") {
+		if err := d.SetNewComputed("custom_adapters"); err != nil {
+			return err
+		}
+	}
+
+	// cdrom_image follows the cloud_init seed; without one it follows the configuration
+	if c, ok := getCloudInitConfig(d.Get("cloud_init")); ok {
+		for _, key := range []string{"name", "cloud_init.0.user_data", "cloud_init.0.meta_data", "cloud_init.0.network_config"} {
+			if !d.NewValueKnown(key) {
+				return d.SetNewComputed("cdrom_image")
+			}
+		}
+		name := d.Get("name").(string)
+		iso := cloudInitISOName(name, cloudInitFiles(name, c))
+		if d.Get("cdrom_image").(string) != iso {
+			return d.SetNew("cdrom_image", iso)
+		}
+	} else if d.GetRawConfig().GetAttr("cdrom_image").IsNull() && d.Get("cdrom_image").(string) != "" {
+		return d.SetNew("cdrom_image", "")
+	}
+	return nil
+}
+
 // ResourceGns3Qemu defines a new Terraform resource for creating a QEMU VM instance in GNS3.
 func resourceGns3Qemu() *schema.Resource {
 	return &schema.Resource{
@@ -22,12 +123,26 @@ func resourceGns3Qemu() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceQemuImporter, // use custom importer
 		},
+		CustomizeDiff: customdiff.All(
+			resourceGns3QemuCustomizeDiff,
+			computePlacementCustomizeDiff("ram"),
+			waitForCustomizeDiff("start_vm"),
+		),
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "The UUID of the GNS3 project",
 			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the QEMU node is running.",
+			},
+			"placement": placementSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -53,11 +168,14 @@ func resourceGns3Qemu() *schema.Resource {
 			"cdrom_image": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Path to the QEMU CDROM image",
+				Computed:    true,
+				Description: "Path to the QEMU CDROM image. Set automatically when cloud_init is used.",
 			},
+			"cloud_init": cloudInitSchema(),
 			"console": {
 				Type:        schema.TypeInt,
 				Optional:    true,
+				Computed:    true,
 				Description: "Console TCP port",
 			},
 			"console_type": {
@@ -81,6 +199,7 @@ func resourceGns3Qemu() *schema.Resource {
 			"mac_address": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				Description: "Explicit MAC address to assign to the VM's primary network interface",
 			},
 			"options": {
@@ -92,11 +211,18 @@ func resourceGns3Qemu() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
+				Deprecated:  "Use status = \"started\" instead.",
 				Description: "If true, start the QEMU VM instance after creation",
 			},
+			"status":           nodeStatusSchema(),
+			"wait_for":         waitForSchema(),
+			"files":            nodeFilesSchema(),
+			"reload_on_change": reloadOnChangeSchema(),
+			"file_hashes":      fileHashesSchema(),
 			"platform": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				Description: "Platform architecture for QEMU node (e.g. x86_64, aarch64). Required to determine QEMU binary.",
 			},
 			"hda_disk_image": {
@@ -104,6 +230,166 @@ func resourceGns3Qemu() *schema.Resource {
 				Optional:    true,
 				Description: "Path to the HDA (bootable) disk image file for the QEMU node",
 			},
+			"hda_disk_interface": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "virtio",
+				ValidateFunc: validation.StringInSlice(qemuDiskInterfaces, false),
+				Description:  "Bus of the HDA disk (ide, sata, scsi, virtio)",
+			},
+			"hdb_disk_image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the HDB disk image file",
+			},
+			"hdb_disk_interface": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "virtio",
+				ValidateFunc: validation.StringInSlice(qemuDiskInterfaces, false),
+				Description:  "Bus of the HDB disk (ide, sata, scsi, virtio)",
+			},
+			"hdc_disk_image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the HDC disk image file",
+			},
+			"hdc_disk_interface": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "virtio",
+				ValidateFunc: validation.StringInSlice(qemuDiskInterfaces, false),
+				Description:  "Bus of the HDC disk (ide, sata, scsi, virtio)",
+			},
+			"hdd_disk_image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the HDD disk image file",
+			},
+			"hdd_disk_interface": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "virtio",
+				ValidateFunc: validation.StringInSlice(qemuDiskInterfaces, false),
+				Description:  "Bus of the HDD disk (ide, sata, scsi, virtio)",
+			},
+			"boot_priority": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "c",
+				ValidateFunc: validation.StringInSlice([]string{"c", "d", "n", "cn", "cd", "dn", "dc", "nc", "nd"}, false),
+				Description:  "Boot order: c (disk), d (cdrom), n (network) or a combination such as cd",
+			},
+			"kernel_image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Kernel image to boot directly instead of a disk",
+			},
+			"initrd": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Initial ramdisk used with kernel_image",
+			},
+			"kernel_command_line": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Kernel command line used with kernel_image",
+			},
+			"cpu_throttling": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 800),
+				Description:  "Percentage of CPU the VM may use, 0 to disable throttling",
+			},
+			"process_priority": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "normal",
+				ValidateFunc: validation.StringInSlice([]string{"realtime", "very high", "high", "normal", "low", "very low"}, false),
+				Description:  "Host process priority of the QEMU process",
+			},
+			"on_close": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "power_off",
+				ValidateFunc: validation.StringInSlice([]string{"power_off", "shutdown_signal", "save_vm_state"}, false),
+				Description:  "Action when the node is closed: power_off, shutdown_signal or save_vm_state",
+			},
+			"linked_clone": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Run the VM from a copy-on-write overlay instead of the base image",
+			},
+			"legacy_networking": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use the legacy -net QEMU networking options",
+			},
+			"replicate_network_connection_state": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Mirror link suspend/resume in the guest's interface state",
+			},
+			"first_port_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the first port, overriding port_name_format for it",
+			},
+			"port_name_format": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "Ethernet{0}",
+				Description: "Port name format, e.g. Gi0/{0}",
+			},
+			"custom_adapters": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+				Description:      "JSON list of per-adapter settings (adapter_number, adapter_type, mac_address, port_name)",
+				ConflictsWith:    []string{"adapter"},
+			},
+			"adapter": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"custom_adapters"},
+				Description:   "Per-adapter settings, mapped to GNS3 custom_adapters",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"adapter_number": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Index of the adapter, below adapters",
+						},
+						"adapter_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "NIC model for this adapter, overriding adapter_type",
+						},
+						"mac_address": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsMACAddress,
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return strings.EqualFold(old, new)
+							},
+							Description: "Fixed MAC address for this adapter",
+						},
+						"port_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Port name for this adapter, overriding port_name_format",
+						},
+					},
+				},
+			},
 			// NEW: optional canvas coordinates
 			"x": {
 				Type:        schema.TypeInt,
@@ -124,52 +410,44 @@ func resourceGns3QemuCreate(d *schema.ResourceData, meta interface{}) error {
 	projectID := d.Get("project_id").(string)
 
 	name := d.Get("name").(string)
-	adapterType := d.Get("adapter_type").(string)
-	adapters := d.Get("adapters").(int)
-	biosImage := d.Get("bios_image").(string)
-	cdromImage, _ := d.GetOk("cdrom_image")
 	consoleVal, consoleOk := d.GetOk("console")
 	consoleType := d.Get("console_type").(string)
-	cpus := d.Get("cpus").(int)
-	ram := d.Get("ram").(int)
-	platform := d.Get("platform").(string)
-
-	properties := map[string]interface{}{
-		"adapter_type": adapterType,
-		"adapters":     adapters,
-		"bios_image":   biosImage,
-		"cdrom_image":  "",
-		"ram":          ram,
-		"cpus":         cpus,
-		"platform":     platform,
-	}
-
-	if cdromImage != nil {
-		properties["cdrom_image"] = cdromImage.(string)
-	}
-	if v, ok := d.GetOk("mac_address"); ok {
-		properties["mac_address"] = v.(string)
-	}
-	if v, ok := d.GetOk("options"); ok {
-		properties["options"] = v.(string)
+	computeID, err := placeComputeID(d, meta, "ram")
+	if err != nil {
+		return err
 	}
-	if v, ok := d.GetOk("hda_disk_image"); ok {
-		properties["hda_disk_image"] = v.(string)
-		properties["hda_disk_interface"] = "virtio"
+	properties := qemuProperties(d)
+	if c, ok := getCloudInitConfig(d.Get("cloud_init")); ok {
+		iso, err := uploadCloudInitISO(config.Host, computeID, name, c)
+		if err != nil {
+			return err
+		}
+		properties["cdrom_image"] = iso
 	}
 
 	// Controller-level API
 	payload := map[string]interface{}{
-		"name":       name,
-		"node_type":  "qemu",
-		"compute_id": "local", // adjust if needed
+		"name":         name,
+		"node_type":    "qemu",
+		"compute_id":   computeID,
 		"console_type": consoleType,
-		"properties": properties,
+		"properties":   properties,
 	}
 
 	if consoleOk {
 		payload["console"] = consoleVal.(int)
 	}
+	if v, ok := d.GetOk("first_port_name"); ok {
+		payload["first_port_name"] = v.(string)
+	}
+	payload["port_name_format"] = d.Get("port_name_format").(string)
+	customAdapters, err := qemuCustomAdapters(d)
+	if err != nil {
+		return err
+	}
+	if len(customAdapters) > 0 {
+		payload["custom_adapters"] = customAdapters
+	}
 
 	// include x/y if explicitly set (even if zero)
 	if xv, ok := d.GetOkExists("x"); ok {
@@ -207,22 +485,17 @@ func resourceGns3QemuCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 	d.SetId(nodeID)
 
-	// Start VM if requested
-	if d.Get("start_vm").(bool) {
-		startURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/start", config.Host, projectID, nodeID)
-		req, err := http.NewRequest("POST", startURL, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create start request: %s", err)
-		}
-		startResp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return fmt.Errorf("failed to start QEMU node: %s", err)
-		}
-		defer startResp.Body.Close()
-		if startResp.StatusCode != http.StatusOK {
-			body, _ := ioutil.ReadAll(startResp.Body)
-			return fmt.Errorf("failed to start node, status: %d, response: %s", startResp.StatusCode, string(body))
-		}
+	// Upload configuration files before the first start
+	if err := uploadNodeFiles(d, config.Host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	// Bring the VM to the requested status (new nodes are created stopped)
+	if err := setNodeStatus(config.Host, projectID, nodeID, "stopped", desiredNodeStatus(d, "start_vm")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, config.Host, projectID, nodeID); err != nil {
+		return err
 	}
 
 	return resourceGns3QemuRead(d, meta)
@@ -255,6 +528,63 @@ func resourceGns3QemuRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.Set("name", node["name"])
+	d.Set("compute_id", node["compute_id"])
+	d.Set("status", node["status"])
+	d.Set("console_type", node["console_type"])
+	if v, ok := node["console"].(float64); ok {
+		d.Set("console", int(v))
+	}
+	d.Set("first_port_name", node["first_port_name"])
+	d.Set("port_name_format", node["port_name_format"])
+
+	props, _ := node["properties"].(map[string]interface{})
+	setNodeProperties(d, props, qemuPropertyKeys)
+
+	customAdapters, ok := node["custom_adapters"]
+	if !ok || customAdapters == nil {
+		customAdapters = props["custom_adapters"]
+	}
+	if customAdapters == nil {
+		customAdapters = []interface{}{}
+	}
+	encoded, err := json.Marshal(customAdapters)
+	if err != nil {
+		return fmt.Errorf("failed to encode custom_adapters: %s", err)
+	}
+	d.Set("custom_adapters", string(encoded))
+
+	// Adapter blocks are only refreshed when they are in use, keeping the declared order
+	if blocks := d.Get("adapter").([]interface{}); len(blocks) > 0 {
+		listed, _ := customAdapters.([]interface{})
+		byNumber := map[int]map[string]interface{}{}
+		for _, raw := range listed {
+			adapter, _ := raw.(map[string]interface{})
+			number, _ := adapter["adapter_number"].(float64)
+			byNumber[int(number)] = adapter
+		}
+		refreshed := make([]interface{}, 0, len(listed))
+		for _, raw := range blocks {
+			number := raw.(map[string]interface{})["adapter_number"].(int)
+			if adapter, ok := byNumber[number]; ok {
+				refreshed = append(refreshed, qemuAdapterBlock(number, adapter))
+				delete(byNumber, number)
+			}
+		}
+		for _, raw := range listed {
+			adapter, _ := raw.(map[string]interface{})
+			number, _ := adapter["adapter_number"].(float64)
+			if _, ok := byNumber[int(number)]; ok {
+				refreshed = append(refreshed, qemuAdapterBlock(int(number), adapter))
+			}
+		}
+		if err := d.Set("adapter", refreshed); err != nil {
+			return fmt.Errorf("failed to set adapter: %s", err)
+		}
+	}
+
+	if err := readNodeFiles(d, config.Host, projectID, nodeID, node); err != nil {
+		return err
+	}
 
 	// hydrate x/y if present
 	if xv, ok := node["x"]; ok {
@@ -277,28 +607,37 @@ func resourceGns3QemuRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// qemuAdapterBlock converts a GNS3 custom adapter into an adapter block.
+func qemuAdapterBlock(number int, adapter map[string]interface{}) map[string]interface{} {
+	block := map[string]interface{}{"adapter_number": number}
+	for _, key := range []string{"adapter_type", "mac_address", "port_name"} {
+		v, _ := adapter[key].(string)
+		block[key] = v
+	}
+	return block
+}
+
 func resourceGns3QemuUpdate(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*ProviderConfig)
 	projectID := d.Get("project_id").(string)
 	nodeID := d.Id()
 
+	if d.HasChanges("files") {
+		if err := uploadNodeFiles(d, config.Host, projectID, nodeID); err != nil {
+			return err
+		}
+	}
+
 	// If nothing changed, just refresh state
-	if !(d.HasChange("name") ||
-		d.HasChange("adapter_type") ||
-		d.HasChange("adapters") ||
-		d.HasChange("bios_image") ||
-		d.HasChange("cdrom_image") ||
-		d.HasChange("console") ||
-		d.HasChange("console_type") ||
-		d.HasChange("cpus") ||
-		d.HasChange("ram") ||
-		d.HasChange("mac_address") ||
-		d.HasChange("options") ||
-		d.HasChange("platform") ||
-		d.HasChange("hda_disk_image") ||
-		d.HasChange("start_vm") ||
-		d.HasChange("x") ||
-		d.HasChange("y")) {
+	if !d.HasChanges(qemuPropertyKeys...) &&
+		!d.HasChanges("name", "console", "console_type", "first_port_name", "port_name_format", "custom_adapters", "adapter", "cloud_init", "start_vm", "x", "y") {
+		// Only the status may differ, which needs no stop/PUT cycle
+		if d.HasChange("status") {
+			oldStatus, _ := d.GetChange("status")
+			if err := setNodeStatus(config.Host, projectID, nodeID, oldStatus.(string), desiredNodeStatus(d, "start_vm")); err != nil {
+				return err
+			}
+		}
 		return resourceGns3QemuRead(d, meta)
 	}
 
@@ -351,54 +690,20 @@ func resourceGns3QemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	// 3) Overlay changed fields into properties (top-level handled separately)
-	if d.HasChange("adapter_type") {
-		props["adapter_type"] = d.Get("adapter_type").(string)
-	}
-	if d.HasChange("adapters") {
-		props["adapters"] = d.Get("adapters").(int)
-	}
-	if d.HasChange("bios_image") {
-		props["bios_image"] = d.Get("bios_image").(string)
-	}
-	if d.HasChange("cdrom_image") {
-		if v, ok := d.GetOk("cdrom_image"); ok {
-			props["cdrom_image"] = v.(string)
-		} else {
-			delete(props, "cdrom_image")
-		}
-	}
-	if d.HasChange("cpus") {
-		props["cpus"] = d.Get("cpus").(int)
-	}
-	if d.HasChange("ram") {
-		props["ram"] = d.Get("ram").(int)
-	}
-	if d.HasChange("mac_address") {
-		if v, ok := d.GetOk("mac_address"); ok {
-			props["mac_address"] = v.(string)
-		} else {
-			delete(props, "mac_address")
+	// 3) Overlay changed fields into properties (top-level handled separately).
+	// Cleared strings are sent as "" so GNS3 drops the old value.
+	for _, key := range qemuPropertyKeys {
+		if d.HasChange(key) {
+			props[key] = d.Get(key)
 		}
 	}
-	if d.HasChange("options") {
-		if v, ok := d.GetOk("options"); ok {
-			props["options"] = v.(string)
-		} else {
-			delete(props, "options")
-		}
-	}
-	if d.HasChange("platform") {
-		props["platform"] = d.Get("platform").(string)
-	}
-	if d.HasChange("hda_disk_image") {
-		if v, ok := d.GetOk("hda_disk_image"); ok {
-			props["hda_disk_image"] = v.(string)
-			props["hda_disk_interface"] = "virtio"
-		} else {
-			delete(props, "hda_disk_image")
-			delete(props, "hda_disk_interface")
+
+	if c, ok := getCloudInitConfig(d.Get("cloud_init")); ok && d.HasChanges("cloud_init", "name") {
+		iso, err := uploadCloudInitISO(config.Host, d.Get("compute_id").(string), d.Get("name").(string), c)
+		if err != nil {
+			return err
 		}
+		props["cdrom_image"] = iso
 	}
 
 	// 4) Build PUT payload (top-level name/x/y + properties)
@@ -409,13 +714,26 @@ func resourceGns3QemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		putPayload["name"] = d.Get("name").(string)
 	}
 	if d.HasChange("console") {
-	  if v, ok := d.GetOk("console"); ok {
-		putPayload["console"] = v.(int)
-	  }
-    }
+		if v, ok := d.GetOk("console"); ok {
+			putPayload["console"] = v.(int)
+		}
+	}
 	if d.HasChange("console_type") {
-      putPayload["console_type"] = d.Get("console_type").(string)
-    }
+		putPayload["console_type"] = d.Get("console_type").(string)
+	}
+	if d.HasChange("first_port_name") {
+		putPayload["first_port_name"] = d.Get("first_port_name").(string)
+	}
+	if d.HasChange("port_name_format") {
+		putPayload["port_name_format"] = d.Get("port_name_format").(string)
+	}
+	if d.HasChanges("custom_adapters", "adapter") {
+		customAdapters, err := qemuCustomAdapters(d)
+		if err != nil {
+			return err
+		}
+		putPayload["custom_adapters"] = customAdapters
+	}
 	if d.HasChange("x") {
 		if xv, ok := d.GetOkExists("x"); ok {
 			putPayload["x"] = xv.(int)
@@ -426,7 +744,7 @@ func resourceGns3QemuUpdate(d *schema.ResourceData, meta interface{}) error {
 			putPayload["y"] = yv.(int)
 		}
 	}
-	
+
 	// 5) PUT update
 	data, err := json.Marshal(putPayload)
 	if err != nil {
@@ -449,22 +767,17 @@ func resourceGns3QemuUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("update QEMU node failed, status: %d, response: %s", putResp.StatusCode, string(body))
 	}
 
-	// 6) Start again if it was running, or if desired state requests it
-	if wasRunning || d.Get("start_vm").(bool) {
-		startURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/start", config.Host, projectID, nodeID)
-		req, err := http.NewRequest("POST", startURL, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create start request: %s", err)
-		}
-		startResp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return fmt.Errorf("failed to start QEMU node: %s", err)
-		}
-		defer startResp.Body.Close()
-		if startResp.StatusCode != http.StatusOK {
-			body, _ := ioutil.ReadAll(startResp.Body)
-			return fmt.Errorf("failed to start node, status: %d, response: %s", startResp.StatusCode, string(body))
-		}
+	// 6) Restore the previous run state, or apply the requested status
+	currentStatus, _ := node["status"].(string)
+	if wasRunning {
+		currentStatus = "stopped"
+	}
+	desired := desiredNodeStatus(d, "start_vm")
+	if desired == "" && wasRunning {
+		desired = "started"
+	}
+	if err := setNodeStatus(config.Host, projectID, nodeID, currentStatus, desired); err != nil {
+		return err
 	}
 
 	// 7) Re-read to sync state
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceGns3ServerSettings defines the Terraform resource schema for the controller settings.
+// Only the sections and keys declared in the configuration are written and tracked.
+func resourceGns3ServerSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3ServerSettingsUpdate,
+		Read:   resourceGns3ServerSettingsRead,
+		Update: resourceGns3ServerSettingsUpdate,
+		Delete: resourceGns3ServerSettingsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"section": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "Settings section, e.g. Qemu, VPCS or IOU, with the keys to manage in it.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the settings section.",
+						},
+						"values": {
+							Type:        schema.TypeMap,
+							Required:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Keys to set in the section. Values are converted to the type of the current value; lists and objects are given as JSON.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getServerSettings reads the controller settings.
+func getServerSettings(host string) (map[string]interface{}, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v2/settings", host))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server settings: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read server settings, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	settings := map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode server settings: %s", err)
+	}
+	return settings, nil
+}
+
+// resourceGns3ServerSettingsUpdate merges the declared keys into the current settings
+// and writes them back, leaving every other key as the GUI left it.
+func resourceGns3ServerSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	settings, err := getServerSettings(config.Host)
+	if err != nil {
+		return err
+	}
+	for _, raw := range d.Get("section").(*schema.Set).List() {
+		section := raw.(map[string]interface{})
+		name := section["name"].(string)
+		current, ok := settings[name].(map[string]interface{})
+		if !ok {
+			current = map[string]interface{}{}
+		}
+		for key, value := range section["values"].(map[string]interface{}) {
+			coerced, err := coercePropertyValue(current[key], value.(string))
+			if err != nil {
+				return fmt.Errorf("invalid value for %s.%s: %s", name, key, err)
+			}
+			current[key] = coerced
+		}
+		settings[name] = current
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal server settings: %s", err)
+	}
+	resp, err := http.Post(fmt.Sprintf("%s/v2/settings", config.Host), "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to update server settings: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update server settings, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("settings")
+	return resourceGns3ServerSettingsRead(d, meta)
+}
+
+func resourceGns3ServerSettingsRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	settings, err := getServerSettings(config.Host)
+	if err != nil {
+		return err
+	}
+
+	// Read back only the keys under management; keys removed on the server drop out
+	// of state so the next apply sets them again
+	sections := []interface{}{}
+	for _, raw := range d.Get("section").(*schema.Set).List() {
+		section := raw.(map[string]interface{})
+		name := section["name"].(string)
+		current, _ := settings[name].(map[string]interface{})
+
+		values := map[string]interface{}{}
+		for key := range section["values"].(map[string]interface{}) {
+			if value, ok := current[key]; ok {
+				values[key] = formatPropertyValue(value)
+			}
+		}
+		sections = append(sections, map[string]interface{}{
+			"name":   name,
+			"values": values,
+		})
+	}
+	if err := d.Set("section", sections); err != nil {
+		return fmt.Errorf("failed to set section: %s", err)
+	}
+	return nil
+}
+
+// resourceGns3ServerSettingsDelete only removes the settings from state; the
+// controller keeps the last written values.
+func resourceGns3ServerSettingsDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -18,6 +18,7 @@ type Switch struct {
 	NodeType  string `json:"node_type"`
 	ComputeID string `json:"compute_id,omitempty"`
 	NodeID    string `json:"node_id,omitempty"`
+	Status    string `json:"status,omitempty"`
 	X         int    `json:"x,omitempty"`
 	Y         int    `json:"y,omitempty"`
 }
@@ -32,6 +33,7 @@ func resourceGns3Switch() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceGns3SwitchImporter,
 		},
+		CustomizeDiff: computePlacementCustomizeDiff(""),
 
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -45,11 +47,14 @@ func resourceGns3Switch() *schema.Resource {
 				Description: "Name of the switch node.",
 			},
 			"compute_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "local",
-				Description: "Compute ID where the switch node is running.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the switch node is running.",
 			},
+			"placement": placementSchema(),
 			"x": { // ✅ Added X coordinate support
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -60,6 +65,7 @@ func resourceGns3Switch() *schema.Resource {
 				Optional:    true,
 				Description: "Y position of the switch node in GNS3 GUI.",
 			},
+			"status": builtinNodeStatusSchema(),
 			"switch_id": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -74,7 +80,10 @@ func resourceGns3SwitchCreate(d *schema.ResourceData, meta interface{}) error {
 	host := config.Host
 	projectID := d.Get("project_id").(string)
 	name := d.Get("name").(string)
-	computeID := d.Get("compute_id").(string)
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
 	x := d.Get("x").(int) // ✅ Retrieve X coordinate
 	y := d.Get("y").(int) // ✅ Retrieve Y coordinate
 
@@ -116,7 +125,8 @@ func resourceGns3SwitchCreate(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(createdSwitch.NodeID)
 	d.Set("switch_id", createdSwitch.NodeID)
-	return nil
+
+	return resourceGns3SwitchRead(d, meta)
 }
 
 // Update function for modifying existing switch nodes
@@ -132,10 +142,6 @@ func resourceGns3SwitchUpdate(d *schema.ResourceData, meta interface{}) error {
 		updateData["name"] = d.Get("name").(string)
 	}
 
-	if d.HasChange("compute_id") {
-		updateData["compute_id"] = d.Get("compute_id").(string)
-	}
-
 	if d.HasChange("x") {
 		updateData["x"] = d.Get("x").(int) // ✅ Update X coordinate
 	}
@@ -145,7 +151,7 @@ func resourceGns3SwitchUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if len(updateData) == 0 {
-		return nil
+		return resourceGns3SwitchRead(d, meta)
 	}
 
 	updateBody, err := json.Marshal(updateData)
@@ -199,7 +205,12 @@ func resourceGns3SwitchRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("failed to read switch node, status code: %d, body: %s", resp.StatusCode, body)
 	}
 
-	// Optional: parse attributes and update d.Set(...)
+	var node map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		return fmt.Errorf("failed to decode switch node: %s", err)
+	}
+	d.Set("status", node["status"])
+
 	return nil
 }
 
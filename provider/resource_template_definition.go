@@ -0,0 +1,358 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// templateImageKeys maps template types to the setting holding their image.
+var templateImageKeys = map[string]string{
+	"qemu":     "hda_disk_image",
+	"docker":   "image",
+	"dynamips": "image",
+	"iou":      "path",
+}
+
+// templateAdapterKeys maps template types to the setting holding their adapter count.
+var templateAdapterKeys = map[string]string{
+	"qemu":       "adapters",
+	"docker":     "adapters",
+	"virtualbox": "adapters",
+	"vmware":     "adapters",
+	"iou":        "ethernet_adapters",
+}
+
+// templateRAMTypes are the template types that accept a ram setting.
+var templateRAMTypes = []string{"qemu", "dynamips", "iou", "virtualbox"}
+
+// resourceGns3TemplateDefinition defines the Terraform resource schema for GNS3 templates.
+func resourceGns3TemplateDefinition() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3TemplateDefinitionCreate,
+		Read:   resourceGns3TemplateDefinitionRead,
+		Update: resourceGns3TemplateDefinitionUpdate,
+		Delete: resourceGns3TemplateDefinitionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceGns3TemplateDefinitionCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the template.",
+			},
+			"template_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"qemu", "docker", "dynamips", "iou", "vpcs", "virtualbox", "vmware",
+					"cloud", "nat", "ethernet_hub", "ethernet_switch", "frame_relay_switch", "atm_switch", "traceng",
+				}, false),
+				Description: "Template type, e.g. qemu, docker, dynamips, iou or vpcs.",
+			},
+			"compute_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "local",
+				Description: "Compute the template's nodes are created on.",
+			},
+			"category": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"router", "switch", "guest", "firewall"}, false),
+				Description:  "GUI category: router, switch, guest or firewall.",
+			},
+			"symbol": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Symbol used for the template's nodes, e.g. :/symbols/router.svg.",
+			},
+			"default_name_format": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name format for new nodes, e.g. R{0}.",
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Image of the template (qemu hda_disk_image, docker/dynamips image, iou path).",
+			},
+			"ram": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "RAM in MB (qemu, dynamips, iou, virtualbox).",
+			},
+			"adapters": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Number of network adapters (qemu, docker, virtualbox, vmware; ethernet_adapters for iou).",
+			},
+			"settings": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressEquivalentJSON,
+				Description:      "JSON object of additional type-specific template settings, e.g. jsonencode({ platform = \"c7200\" }).",
+			},
+			"builtin": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the template is built into GNS3.",
+			},
+		},
+	}
+}
+
+// suppressEquivalentJSON ignores formatting and key order differences between JSON strings.
+func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
+	var o, n interface{}
+	if err := json.Unmarshal([]byte(old), &o); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &n); err != nil {
+		return false
+	}
+	ob, _ := json.Marshal(o)
+	nb, _ := json.Marshal(n)
+	return bytes.Equal(ob, nb)
+}
+
+// resourceGns3TemplateDefinitionCustomizeDiff rejects image, ram and adapters for template
+// types without them. Only the configuration is checked: the attributes are also
+// computed, and the state of a template being replaced by another type still holds
+// the old type's values.
+func resourceGns3TemplateDefinitionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("template_type") {
+		return nil
+	}
+	templateType := d.Get("template_type").(string)
+	config := d.GetRawConfig()
+
+	if !config.GetAttr("image").IsNull() {
+		if _, supported := templateImageKeys[templateType]; !supported {
+			return fmt.Errorf("image is not supported for template_type %q", templateType)
+		}
+	}
+	if !config.GetAttr("ram").IsNull() && !containsString(templateRAMTypes, templateType) {
+		return fmt.Errorf("ram is not supported for template_type %q", templateType)
+	}
+	if !config.GetAttr("adapters").IsNull() {
+		if _, supported := templateAdapterKeys[templateType]; !supported {
+			return fmt.Errorf("adapters is not supported for template_type %q", templateType)
+		}
+	}
+	return nil
+}
+
+// templateDefinitionPayload builds the /v2/templates request body from the resource data.
+func templateDefinitionPayload(d *schema.ResourceData) (map[string]interface{}, error) {
+	payload := map[string]interface{}{}
+	if raw, ok := d.GetOk("settings"); ok {
+		if err := json.Unmarshal([]byte(raw.(string)), &payload); err != nil {
+			return nil, fmt.Errorf("failed to parse settings: %s", err)
+		}
+	}
+
+	templateType := d.Get("template_type").(string)
+	payload["name"] = d.Get("name").(string)
+	payload["template_type"] = templateType
+	payload["compute_id"] = d.Get("compute_id").(string)
+
+	for _, key := range []string{"category", "symbol", "default_name_format"} {
+		if v, ok := d.GetOk(key); ok {
+			payload[key] = v.(string)
+		}
+	}
+	if v, ok := d.GetOk("image"); ok {
+		if key, supported := templateImageKeys[templateType]; supported {
+			payload[key] = v.(string)
+		}
+	}
+	if v, ok := d.GetOk("ram"); ok && containsString(templateRAMTypes, templateType) {
+		payload["ram"] = v.(int)
+	}
+	if v, ok := d.GetOk("adapters"); ok {
+		if key, supported := templateAdapterKeys[templateType]; supported {
+			payload[key] = v.(int)
+		}
+	}
+
+	return payload, nil
+}
+
+func resourceGns3TemplateDefinitionCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	payload, err := templateDefinitionPayload(d)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/templates", config.Host)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create template: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create template, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return fmt.Errorf("failed to decode template response: %s", err)
+	}
+	templateID, ok := created["template_id"].(string)
+	if !ok || templateID == "" {
+		return fmt.Errorf("failed to retrieve template_id from GNS3 API response")
+	}
+	d.SetId(templateID)
+
+	return resourceGns3TemplateDefinitionRead(d, meta)
+}
+
+func resourceGns3TemplateDefinitionRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	url := fmt.Sprintf("%s/v2/templates/%s", config.Host, d.Id())
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to read template, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var template map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&template); err != nil {
+		return fmt.Errorf("failed to decode template: %s", err)
+	}
+
+	templateType, _ := template["template_type"].(string)
+	d.Set("name", template["name"])
+	d.Set("template_type", templateType)
+	d.Set("category", template["category"])
+	d.Set("symbol", template["symbol"])
+	d.Set("default_name_format", template["default_name_format"])
+	d.Set("builtin", template["builtin"])
+	if v, ok := template["compute_id"].(string); ok && v != "" {
+		d.Set("compute_id", v)
+	}
+	if key, supported := templateImageKeys[templateType]; supported {
+		d.Set("image", template[key])
+	}
+	if v, ok := template["ram"].(float64); ok && containsString(templateRAMTypes, templateType) {
+		d.Set("ram", int(v))
+	}
+	if key, supported := templateAdapterKeys[templateType]; supported {
+		if v, ok := template[key].(float64); ok {
+			d.Set("adapters", int(v))
+		}
+	}
+
+	// Only the keys declared in settings are tracked, so server-side defaults don't show as drift
+	if raw, ok := d.GetOk("settings"); ok {
+		var declared map[string]interface{}
+		if err := json.Unmarshal([]byte(raw.(string)), &declared); err == nil {
+			current := map[string]interface{}{}
+			for key := range declared {
+				if v, ok := template[key]; ok {
+					current[key] = v
+				}
+			}
+			encoded, err := json.Marshal(current)
+			if err != nil {
+				return fmt.Errorf("failed to encode settings: %s", err)
+			}
+			d.Set("settings", string(encoded))
+		}
+	}
+
+	return nil
+}
+
+func resourceGns3TemplateDefinitionUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	payload, err := templateDefinitionPayload(d)
+	if err != nil {
+		return err
+	}
+	delete(payload, "template_type")
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/templates/%s", config.Host, d.Id())
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create update request for template: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update template, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	return resourceGns3TemplateDefinitionRead(d, meta)
+}
+
+func resourceGns3TemplateDefinitionDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	url := fmt.Sprintf("%s/v2/templates/%s", config.Host, d.Id())
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for template: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete template, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
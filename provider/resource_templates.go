@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -22,6 +24,11 @@ func resourceGns3Template() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceGns3TemplateImporter,
 		},
+		CustomizeDiff: customdiff.All(
+			resourceGns3TemplateCustomizeDiff,
+			computePlacementCustomizeDiffWithRAM(templatePlacementRAM),
+			waitForCustomizeDiff("start"),
+		),
 
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -29,24 +36,55 @@ func resourceGns3Template() *schema.Resource {
 				Required: true,
 			},
 			"template_id": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true, // Ensures deletion & recreation if template_id changes
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true, // Ensures deletion & recreation if template_id changes
+				ExactlyOneOf: []string{"template_id", "template_name"},
+			},
+			"template_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Name of the template, resolved to its ID at creation. Alternative to template_id.",
+			},
+			"properties_override": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Node properties to override after creation, e.g. { ram = \"2048\" }. Values are converted to the type of the template's property.",
+			},
+			"properties": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Effective node properties as reported by GNS3.",
 			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"compute_id": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "local",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
 			},
+			"placement": placementSchema(),
 			"start": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+				Type:       schema.TypeBool,
+				Optional:   true,
+				Default:    false,
+				Deprecated: "Use status instead.",
 			},
+			"status":           nodeStatusSchema(),
+			"wait_for":         waitForSchema(),
+			"startup_config":   nodeConfigSchema("Startup configuration uploaded before the node starts (IOU, Dynamips and VPCS nodes)."),
+			"private_config":   nodeConfigSchema("Private configuration uploaded before the node starts (IOU and Dynamips nodes)."),
+			"files":            nodeFilesSchema(),
+			"reload_on_change": reloadOnChangeSchema(),
+			"file_hashes":      fileHashesSchema(),
 			"x": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -61,13 +99,114 @@ func resourceGns3Template() *schema.Resource {
 	}
 }
 
+// resourceGns3TemplateCustomizeDiff marks the effective properties unknown when overrides change.
+func resourceGns3TemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("properties_override") {
+		return d.SetNewComputed("properties")
+	}
+	return nil
+}
+
+// templateNodeRAM returns the RAM in MB of a node created from the template: the ram
+// of properties_override when set, else the template's. Templates without RAM, such
+// as VPCS or Docker ones, need none.
+func templateNodeRAM(host, templateID string, overrides map[string]interface{}) (int, error) {
+	if raw, ok := overrides["ram"].(string); ok {
+		ram, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid properties_override value for \"ram\": %s", err)
+		}
+		return ram, nil
+	}
+
+	templates, err := listTemplates(host)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read template %s: %s", templateID, err)
+	}
+	for _, template := range templates {
+		id, _ := template["template_id"].(string)
+		if id == "" {
+			id, _ = template["id"].(string)
+		}
+		if id == templateID {
+			ram, _ := template["ram"].(float64)
+			return int(ram), nil
+		}
+	}
+	return 0, fmt.Errorf("template %s not found", templateID)
+}
+
+// templatePlacementRAM looks up the RAM of a planned template node for automatic placement.
+func templatePlacementRAM(d *schema.ResourceDiff, meta interface{}) (int, bool, error) {
+	if !d.NewValueKnown("template_id") || !d.NewValueKnown("template_name") || !d.NewValueKnown("properties_override") {
+		return 0, false, nil
+	}
+	host := meta.(*ProviderConfig).Host
+	templateID := d.Get("template_id").(string)
+	if templateID == "" {
+		id, err := getTemplateID(host, d.Get("template_name").(string))
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to resolve template_name: %s", err)
+		}
+		templateID = id
+	}
+	ram, err := templateNodeRAM(host, templateID, d.Get("properties_override").(map[string]interface{}))
+	return ram, err == nil, err
+}
+
+// applyPropertiesOverride converts the override values to the node's property types and PUTs them.
+func applyPropertiesOverride(d *schema.ResourceData, host, projectID, nodeID string) error {
+	overrides := d.Get("properties_override").(map[string]interface{})
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("node %s not found while applying properties_override", nodeID)
+	}
+	current, _ := node["properties"].(map[string]interface{})
+
+	properties := map[string]interface{}{}
+	for key, raw := range overrides {
+		value, err := coercePropertyValue(current[key], raw.(string))
+		if err != nil {
+			return fmt.Errorf("invalid properties_override value for %q: %s", key, err)
+		}
+		properties[key] = value
+	}
+	return updateNodeProperties(host, projectID, nodeID, properties)
+}
+
 func resourceGns3TemplateCreate(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*ProviderConfig)
 	host := config.Host
 	projectID := d.Get("project_id").(string)
 	templateID := d.Get("template_id").(string)
+	if templateID == "" {
+		id, err := getTemplateID(host, d.Get("template_name").(string))
+		if err != nil {
+			return fmt.Errorf("failed to resolve template_name: %s", err)
+		}
+		templateID = id
+		d.Set("template_id", templateID)
+	}
 	templateName := d.Get("name").(string)
-	computeID := d.Get("compute_id").(string)
+	ramMB := 0
+	if _, auto := autoComputeLabel(d.Get("compute_id").(string)); auto {
+		ram, err := templateNodeRAM(host, templateID, d.Get("properties_override").(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		ramMB = ram
+	}
+	computeID, err := placeComputeIDWithRAM(d, meta, ramMB)
+	if err != nil {
+		return err
+	}
 	x := d.Get("x").(int)
 	y := d.Get("y").(int)
 
@@ -108,20 +247,25 @@ func resourceGns3TemplateCreate(d *schema.ResourceData, meta interface{}) error
 	// Set the resource ID in Terraform
 	d.SetId(templateNodeID)
 
-	// Check if the "start" attribute is true and start the node if so.
-	if d.Get("start").(bool) {
-		startURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/start", host, projectID, templateNodeID)
-		startResp, err := http.Post(startURL, "application/json", nil)
-		if err != nil {
-			return fmt.Errorf("error starting node: %s", err)
-		}
-		defer startResp.Body.Close()
-		if startResp.StatusCode != http.StatusOK {
-			return fmt.Errorf("failed to start node, status code: %d", startResp.StatusCode)
-		}
+	// Per-instance overrides are applied while the node is still stopped
+	if err := applyPropertiesOverride(d, host, projectID, templateNodeID); err != nil {
+		return err
 	}
 
-	return nil
+	// Upload configuration files before the first start
+	if err := uploadNodeFiles(d, host, projectID, templateNodeID); err != nil {
+		return err
+	}
+
+	// Bring the node to the requested status (new nodes are created stopped)
+	if err := setNodeStatus(host, projectID, templateNodeID, "stopped", desiredNodeStatus(d, "start")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, templateNodeID); err != nil {
+		return err
+	}
+
+	return resourceGns3TemplateRead(d, meta)
 }
 
 func resourceGns3TemplateRead(d *schema.ResourceData, meta interface{}) error {
@@ -147,6 +291,40 @@ func resourceGns3TemplateRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("failed to read template node, status code: %d, response: %s", resp.StatusCode, string(body))
 	}
 
+	var node map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		return fmt.Errorf("error decoding template node: %s", err)
+	}
+	d.Set("status", node["status"])
+	if v, ok := node["template_id"].(string); ok && v != "" {
+		d.Set("template_id", v)
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	effective := make(map[string]string, len(props))
+	for key, value := range props {
+		effective[key] = formatPropertyValue(value)
+	}
+	if err := d.Set("properties", effective); err != nil {
+		return fmt.Errorf("failed to set properties: %s", err)
+	}
+
+	// Track only the overridden keys so drift on them shows up in the plan
+	overrides := d.Get("properties_override").(map[string]interface{})
+	if len(overrides) > 0 {
+		current := make(map[string]string, len(overrides))
+		for key := range overrides {
+			current[key] = effective[key]
+		}
+		if err := d.Set("properties_override", current); err != nil {
+			return fmt.Errorf("failed to set properties_override: %s", err)
+		}
+	}
+
+	if err := readNodeFiles(d, host, projectID, nodeID, node); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -188,6 +366,36 @@ func resourceGns3TemplateUpdate(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("failed to update template, status code: %d", resp.StatusCode)
 	}
 
+	oldStatus, _ := d.GetChange("status")
+	currentStatus := oldStatus.(string)
+	desired := desiredNodeStatus(d, "")
+
+	if d.HasChange("properties_override") {
+		// Most emulators only accept property changes while the node is stopped
+		if currentStatus != "stopped" {
+			if err := nodeAction(host, projectID, templateID, "stop"); err != nil {
+				return err
+			}
+			if desired == "" {
+				desired = currentStatus
+			}
+			currentStatus = "stopped"
+		}
+		if err := applyPropertiesOverride(d, host, projectID, templateID); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("startup_config", "private_config", "files") {
+		if err := uploadNodeFiles(d, host, projectID, templateID); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeStatus(host, projectID, templateID, currentStatus, desired); err != nil {
+		return err
+	}
+
 	// Optionally, re-read the resource to update state.
 	return resourceGns3TemplateRead(d, meta)
 }
@@ -0,0 +1,335 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// virtualBoxPropertyKeys are the node properties managed one-to-one by the resource.
+var virtualBoxPropertyKeys = []string{
+	"vmname", "linked_clone", "adapters", "adapter_type", "ram", "headless", "use_any_adapter",
+}
+
+// resourceGns3VirtualBoxNode defines the Terraform resource schema for VirtualBox VM nodes.
+func resourceGns3VirtualBoxNode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3VirtualBoxNodeCreate,
+		Read:   resourceGns3VirtualBoxNodeRead,
+		Update: resourceGns3VirtualBoxNodeUpdate,
+		Delete: resourceGns3VirtualBoxNodeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3VirtualBoxNodeImporter,
+		},
+		CustomizeDiff: customdiff.All(
+			computePlacementCustomizeDiff("ram"),
+			waitForCustomizeDiff(""),
+		),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The project ID where the VirtualBox node is created.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the VirtualBox node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the VM is registered.",
+			},
+			"placement": placementSchema(),
+			"vmname": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the VirtualBox VM on the compute (see the gns3_virtualbox_vms data source).",
+			},
+			"linked_clone": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Run a linked clone of the VM instead of the VM itself.",
+			},
+			"adapters": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(0, 36),
+				Description:  "Number of network adapters.",
+			},
+			"adapter_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "Intel PRO/1000 MT Desktop (82540EM)",
+				Description: "VirtualBox adapter type.",
+			},
+			"ram": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "RAM in MB. Defaults to the VM's own setting.",
+			},
+			"headless": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start the VM without a GUI window.",
+			},
+			"use_any_adapter": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow GNS3 to use adapters that are already configured in VirtualBox.",
+			},
+			"console": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Console TCP port. Allocated by GNS3 when not set.",
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the node in GNS3 GUI.",
+			},
+			"status":   nodeStatusSchema(),
+			"wait_for": waitForSchema(),
+		},
+	}
+}
+
+func resourceGns3VirtualBoxNodeCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	// Booleans are sent even when false; RAM is left to the VM unless set
+	properties := map[string]interface{}{}
+	for _, key := range virtualBoxPropertyKeys {
+		properties[key] = d.Get(key)
+	}
+	if _, ok := d.GetOk("ram"); !ok {
+		delete(properties, "ram")
+	}
+
+	computeID, err := placeComputeID(d, meta, "ram")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"name":       d.Get("name").(string),
+		"node_type":  "virtualbox",
+		"compute_id": computeID,
+		"properties": properties,
+	}
+	if v, ok := d.GetOk("console"); ok {
+		payload["console"] = v.(int)
+	}
+	if xv, ok := d.GetOkExists("x"); ok {
+		payload["x"] = xv.(int)
+	}
+	if yv, ok := d.GetOkExists("y"); ok {
+		payload["y"] = yv.(int)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal VirtualBox node data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create VirtualBox node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create VirtualBox node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return fmt.Errorf("failed to decode VirtualBox node response: %s", err)
+	}
+	nodeID, ok := created["node_id"].(string)
+	if !ok || nodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+	d.SetId(nodeID)
+
+	if err := setNodeStatus(host, projectID, nodeID, "stopped", desiredNodeStatus(d, "")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	return resourceGns3VirtualBoxNodeRead(d, meta)
+}
+
+func resourceGns3VirtualBoxNodeRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("compute_id", node["compute_id"])
+	d.Set("status", node["status"])
+	if v, ok := node["console"].(float64); ok {
+		d.Set("console", int(v))
+	}
+	if v, ok := node["x"].(float64); ok {
+		d.Set("x", int(v))
+	}
+	if v, ok := node["y"].(float64); ok {
+		d.Set("y", int(v))
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	setNodeProperties(d, props, virtualBoxPropertyKeys)
+
+	return nil
+}
+
+func resourceGns3VirtualBoxNodeUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	oldStatus, _ := d.GetChange("status")
+	currentStatus := oldStatus.(string)
+	desired := desiredNodeStatus(d, "")
+
+	putPayload := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	for _, key := range virtualBoxPropertyKeys {
+		if d.HasChange(key) {
+			properties[key] = d.Get(key)
+		}
+	}
+	if len(properties) > 0 {
+		// VirtualBox only accepts hardware changes while the VM is powered off
+		if currentStatus != "stopped" {
+			if err := nodeAction(host, projectID, nodeID, "stop"); err != nil {
+				return err
+			}
+			if desired == "" {
+				desired = currentStatus
+			}
+			currentStatus = "stopped"
+		}
+		putPayload["properties"] = properties
+	}
+	if d.HasChange("name") {
+		putPayload["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("console") {
+		if v, ok := d.GetOk("console"); ok {
+			putPayload["console"] = v.(int)
+		}
+	}
+	if d.HasChange("x") {
+		putPayload["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		putPayload["y"] = d.Get("y").(int)
+	}
+
+	if len(putPayload) > 0 {
+		if err := updateNode(host, projectID, nodeID, putPayload); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeStatus(host, projectID, nodeID, currentStatus, desired); err != nil {
+		return err
+	}
+
+	return resourceGns3VirtualBoxNodeRead(d, meta)
+}
+
+func resourceGns3VirtualBoxNodeDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for VirtualBox node: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete VirtualBox node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete VirtualBox node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3VirtualBoxNodeImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
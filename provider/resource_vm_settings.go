@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// gns3VMSettingKeys are the /v2/gns3vm settings managed by gns3_vm_settings.
+var gns3VMSettingKeys = []string{"enable", "engine", "vmname", "vcpus", "ram", "when_exit", "headless"}
+
+// resourceGns3VMSettings defines the Terraform resource schema for the controller's GNS3 VM settings.
+// The settings are a singleton; unset attributes keep their current value and are read back.
+func resourceGns3VMSettings() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3VMSettingsUpdate,
+		Read:   resourceGns3VMSettingsRead,
+		Update: resourceGns3VMSettingsUpdate,
+		Delete: resourceGns3VMSettingsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enable": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the controller uses the GNS3 VM.",
+			},
+			"engine": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"vmware", "virtualbox", "hyper-v", "remote", "none"}, false),
+				Description:  "Virtualization engine running the GNS3 VM.",
+			},
+			"vmname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the GNS3 VM in the engine.",
+			},
+			"vcpus": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of vCPUs allocated to the GNS3 VM.",
+			},
+			"ram": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "RAM in MB allocated to the GNS3 VM.",
+			},
+			"when_exit": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"stop", "suspend", "keep"}, false),
+				Description:  "What happens to the GNS3 VM when the controller exits.",
+			},
+			"headless": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Start the GNS3 VM without a window.",
+			},
+		},
+	}
+}
+
+// getGNS3VMSettings reads the controller's GNS3 VM settings.
+func getGNS3VMSettings(host string) (map[string]interface{}, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/v2/gns3vm", host))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GNS3 VM settings: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read GNS3 VM settings, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	settings := map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("failed to decode GNS3 VM settings: %s", err)
+	}
+	return settings, nil
+}
+
+// resourceGns3VMSettingsUpdate merges the configured attributes into the current
+// settings, so settings left out of the configuration are not reset.
+func resourceGns3VMSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	settings, err := getGNS3VMSettings(config.Host)
+	if err != nil {
+		return err
+	}
+	raw := d.GetRawConfig()
+	for _, key := range gns3VMSettingKeys {
+		if !raw.GetAttr(key).IsNull() {
+			settings[key] = d.Get(key)
+		}
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal GNS3 VM settings: %s", err)
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/v2/gns3vm", config.Host), bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create GNS3 VM settings request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update GNS3 VM settings: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update GNS3 VM settings, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("gns3vm")
+	return resourceGns3VMSettingsRead(d, meta)
+}
+
+func resourceGns3VMSettingsRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+
+	settings, err := getGNS3VMSettings(config.Host)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range []string{"enable", "headless"} {
+		if v, ok := settings[key].(bool); ok {
+			d.Set(key, v)
+		}
+	}
+	for _, key := range []string{"engine", "vmname", "when_exit"} {
+		if v, ok := settings[key].(string); ok {
+			d.Set(key, v)
+		}
+	}
+	for _, key := range []string{"vcpus", "ram"} {
+		if v, ok := settings[key].(float64); ok {
+			d.Set(key, int(v))
+		}
+	}
+	return nil
+}
+
+// resourceGns3VMSettingsDelete only removes the settings from state; the controller
+// always has GNS3 VM settings, and resetting them could stop a running VM.
+func resourceGns3VMSettingsDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
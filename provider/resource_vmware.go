@@ -0,0 +1,333 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// vmwarePropertyKeys are the node properties managed one-to-one by the resource.
+// GNS3 has no RAM property for VMware VMs; memory comes from the .vmx file, and the
+// resource's ram is only used for placement.
+var vmwarePropertyKeys = []string{
+	"vmx_path", "linked_clone", "adapters", "adapter_type", "headless", "use_any_adapter",
+}
+
+// resourceGns3VMwareNode defines the Terraform resource schema for VMware VM nodes.
+func resourceGns3VMwareNode() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3VMwareNodeCreate,
+		Read:   resourceGns3VMwareNodeRead,
+		Update: resourceGns3VMwareNodeUpdate,
+		Delete: resourceGns3VMwareNodeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3VMwareNodeImporter,
+		},
+		CustomizeDiff: customdiff.All(
+			computePlacementCustomizeDiff("ram"),
+			waitForCustomizeDiff(""),
+		),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The project ID where the VMware node is created.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the VMware node.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the VM is registered.",
+			},
+			"placement": placementSchema(),
+			"vmx_path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Path to the VM's .vmx file on the compute (see the gns3_vmware_vms data source).",
+			},
+			"linked_clone": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Run a linked clone of the VM instead of the VM itself.",
+			},
+			"adapters": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(0, 36),
+				Description:  "Number of network adapters.",
+			},
+			"adapter_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "e1000",
+				Description: "VMware adapter type (e1000, e1000e, vlance, vmxnet2, vmxnet3).",
+			},
+			"headless": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Start the VM without a GUI window.",
+			},
+			"use_any_adapter": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow GNS3 to use adapters that are already configured in the VM.",
+			},
+			"ram": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Memory of the VM in MB. GNS3 takes the memory from the .vmx file and cannot report it, so this is only used to check free RAM when compute_id is \"auto\".",
+			},
+			"console": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Console TCP port. Allocated by GNS3 when not set.",
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the node in GNS3 GUI.",
+			},
+			"status":   nodeStatusSchema(),
+			"wait_for": waitForSchema(),
+		},
+	}
+}
+
+func resourceGns3VMwareNodeCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	properties := map[string]interface{}{}
+	for _, key := range vmwarePropertyKeys {
+		properties[key] = d.Get(key)
+	}
+
+	computeID, err := placeComputeID(d, meta, "ram")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"name":       d.Get("name").(string),
+		"node_type":  "vmware",
+		"compute_id": computeID,
+		"properties": properties,
+	}
+	if v, ok := d.GetOk("console"); ok {
+		payload["console"] = v.(int)
+	}
+	if xv, ok := d.GetOkExists("x"); ok {
+		payload["x"] = xv.(int)
+	}
+	if yv, ok := d.GetOkExists("y"); ok {
+		payload["y"] = yv.(int)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal VMware node data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create VMware node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create VMware node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return fmt.Errorf("failed to decode VMware node response: %s", err)
+	}
+	nodeID, ok := created["node_id"].(string)
+	if !ok || nodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+	d.SetId(nodeID)
+
+	if err := setNodeStatus(host, projectID, nodeID, "stopped", desiredNodeStatus(d, "")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	return resourceGns3VMwareNodeRead(d, meta)
+}
+
+func resourceGns3VMwareNodeRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("compute_id", node["compute_id"])
+	d.Set("status", node["status"])
+	if v, ok := node["console"].(float64); ok {
+		d.Set("console", int(v))
+	}
+	if v, ok := node["x"].(float64); ok {
+		d.Set("x", int(v))
+	}
+	if v, ok := node["y"].(float64); ok {
+		d.Set("y", int(v))
+	}
+
+	props, _ := node["properties"].(map[string]interface{})
+	setNodeProperties(d, props, vmwarePropertyKeys)
+
+	return nil
+}
+
+func resourceGns3VMwareNodeUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	oldStatus, _ := d.GetChange("status")
+	currentStatus := oldStatus.(string)
+	desired := desiredNodeStatus(d, "")
+
+	putPayload := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	for _, key := range vmwarePropertyKeys {
+		if d.HasChange(key) {
+			properties[key] = d.Get(key)
+		}
+	}
+	if len(properties) > 0 {
+		// VMware only accepts hardware changes while the VM is powered off
+		if currentStatus != "stopped" {
+			if err := nodeAction(host, projectID, nodeID, "stop"); err != nil {
+				return err
+			}
+			if desired == "" {
+				desired = currentStatus
+			}
+			currentStatus = "stopped"
+		}
+		putPayload["properties"] = properties
+	}
+	if d.HasChange("name") {
+		putPayload["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("console") {
+		if v, ok := d.GetOk("console"); ok {
+			putPayload["console"] = v.(int)
+		}
+	}
+	if d.HasChange("x") {
+		putPayload["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		putPayload["y"] = d.Get("y").(int)
+	}
+
+	if len(putPayload) > 0 {
+		if err := updateNode(host, projectID, nodeID, putPayload); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeStatus(host, projectID, nodeID, currentStatus, desired); err != nil {
+		return err
+	}
+
+	return resourceGns3VMwareNodeRead(d, meta)
+}
+
+func resourceGns3VMwareNodeDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for VMware node: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete VMware node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete VMware node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3VMwareNodeImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// vpcsStartupScript is the file VPCS executes when it starts.
+const vpcsStartupScript = "startup.vpc"
+
+// resourceGns3Vpcs defines the Terraform resource schema for GNS3 VPCS hosts.
+func resourceGns3Vpcs() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGns3VpcsCreate,
+		Read:   resourceGns3VpcsRead,
+		Update: resourceGns3VpcsUpdate,
+		Delete: resourceGns3VpcsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceGns3VpcsImporter,
+		},
+		CustomizeDiff: customdiff.All(
+			resourceGns3VpcsCustomizeDiff,
+			computePlacementCustomizeDiff(""),
+			waitForCustomizeDiff(""),
+		),
+
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The project ID where the VPCS node is created.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the VPCS node, also used as the VPCS pcname.",
+			},
+			"compute_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "local",
+				DiffSuppressFunc: suppressAutoComputeID,
+				Description:      "Compute ID where the VPCS node runs.",
+			},
+			"placement": placementSchema(),
+			"console": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Console TCP port. Allocated by GNS3 when not set.",
+			},
+			"console_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "telnet",
+				ValidateFunc: validation.StringInSlice([]string{"telnet", "none"}, false),
+				Description:  "Console type (telnet or none).",
+			},
+			"x": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "X position of the VPCS node in GNS3 GUI.",
+			},
+			"y": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Y position of the VPCS node in GNS3 GUI.",
+			},
+			"ip": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.Any(
+					validation.StringInSlice([]string{"dhcp"}, false),
+					validation.IsCIDR,
+				),
+				Description: "IPv4 address in CIDR notation (e.g. 10.0.0.10/24), or dhcp.",
+			},
+			"gateway": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+				Description:  "Default gateway. Ignored when ip is dhcp.",
+			},
+			"dns": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+				Description:  "DNS server address.",
+			},
+			"startup_script": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Content of the startup.vpc script on the node.",
+			},
+			"status":   nodeStatusSchema(),
+			"wait_for": waitForSchema(),
+		},
+	}
+}
+
+// renderVpcsStartupScript builds the startup.vpc content from the IP settings.
+func renderVpcsStartupScript(name, ip, gateway, dns string) string {
+	lines := []string{"set pcname " + name}
+	switch {
+	case ip == "dhcp":
+		lines = append(lines, "ip dhcp")
+	case ip != "" && gateway != "":
+		lines = append(lines, fmt.Sprintf("ip %s %s", ip, gateway))
+	case ip != "":
+		lines = append(lines, "ip "+ip)
+	}
+	if dns != "" {
+		lines = append(lines, "ip dns "+dns)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// resourceGns3VpcsCustomizeDiff plans a script upload whenever the rendered
+// startup.vpc differs from the one read back from the node.
+func resourceGns3VpcsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("name") || !d.NewValueKnown("ip") || !d.NewValueKnown("gateway") || !d.NewValueKnown("dns") {
+		return d.SetNewComputed("startup_script")
+	}
+	script := renderVpcsStartupScript(
+		d.Get("name").(string),
+		d.Get("ip").(string),
+		d.Get("gateway").(string),
+		d.Get("dns").(string),
+	)
+	if d.Get("startup_script").(string) != script {
+		return d.SetNew("startup_script", script)
+	}
+	return nil
+}
+
+func resourceGns3VpcsCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+
+	computeID, err := placeComputeID(d, meta, "")
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"name":         d.Get("name").(string),
+		"node_type":    "vpcs",
+		"compute_id":   computeID,
+		"console_type": d.Get("console_type").(string),
+	}
+	if v, ok := d.GetOk("console"); ok {
+		payload["console"] = v.(int)
+	}
+	if xv, ok := d.GetOkExists("x"); ok {
+		payload["x"] = xv.(int)
+	}
+	if yv, ok := d.GetOkExists("y"); ok {
+		payload["y"] = yv.(int)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal VPCS node data: %s", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes", host, projectID)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("error creating GNS3 VPCS node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create VPCS node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return fmt.Errorf("failed to decode VPCS node response: %s", err)
+	}
+	nodeID, ok := created["node_id"].(string)
+	if !ok || nodeID == "" {
+		return fmt.Errorf("failed to retrieve node_id from GNS3 API response")
+	}
+	d.SetId(nodeID)
+
+	// The script must be in place before VPCS starts
+	script := renderVpcsStartupScript(d.Get("name").(string), d.Get("ip").(string), d.Get("gateway").(string), d.Get("dns").(string))
+	if err := uploadNodeFile(host, projectID, nodeID, vpcsStartupScript, script); err != nil {
+		return err
+	}
+
+	if err := setNodeStatus(host, projectID, nodeID, "stopped", desiredNodeStatus(d, "")); err != nil {
+		return err
+	}
+	if err := waitForNodeConsole(d, host, projectID, nodeID); err != nil {
+		return err
+	}
+
+	return resourceGns3VpcsRead(d, meta)
+}
+
+func resourceGns3VpcsRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	node, found, err := getNode(host, projectID, nodeID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", node["name"])
+	d.Set("compute_id", node["compute_id"])
+	d.Set("console_type", node["console_type"])
+	d.Set("status", node["status"])
+	if v, ok := node["console"].(float64); ok {
+		d.Set("console", int(v))
+	}
+	if v, ok := node["x"].(float64); ok {
+		d.Set("x", int(v))
+	}
+	if v, ok := node["y"].(float64); ok {
+		d.Set("y", int(v))
+	}
+
+	script, _, err := readNodeFile(host, projectID, nodeID, vpcsStartupScript)
+	if err != nil {
+		return err
+	}
+	d.Set("startup_script", script)
+
+	return nil
+}
+
+func resourceGns3VpcsUpdate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	updateData := map[string]interface{}{}
+	if d.HasChange("name") {
+		updateData["name"] = d.Get("name").(string)
+	}
+	if d.HasChange("console") {
+		if v, ok := d.GetOk("console"); ok {
+			updateData["console"] = v.(int)
+		}
+	}
+	if d.HasChange("console_type") {
+		updateData["console_type"] = d.Get("console_type").(string)
+	}
+	if d.HasChange("x") {
+		updateData["x"] = d.Get("x").(int)
+	}
+	if d.HasChange("y") {
+		updateData["y"] = d.Get("y").(int)
+	}
+
+	if len(updateData) > 0 {
+		data, err := json.Marshal(updateData)
+		if err != nil {
+			return fmt.Errorf("failed to marshal update data: %s", err)
+		}
+
+		url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(data))
+		if err != nil {
+			return fmt.Errorf("failed to create update request: %s", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("error updating GNS3 VPCS node: %s", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(resp.Body)
+			return fmt.Errorf("failed to update VPCS node, status code: %d, response: %s", resp.StatusCode, string(body))
+		}
+	}
+
+	oldStatus, _ := d.GetChange("status")
+	if d.HasChange("startup_script") {
+		if err := uploadNodeFile(host, projectID, nodeID, vpcsStartupScript, d.Get("startup_script").(string)); err != nil {
+			return err
+		}
+		// VPCS only runs the script at start, so a running node is reloaded
+		if oldStatus.(string) == "started" {
+			if err := nodeAction(host, projectID, nodeID, "reload"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("status") {
+		if err := setNodeStatus(host, projectID, nodeID, oldStatus.(string), desiredNodeStatus(d, "")); err != nil {
+			return err
+		}
+	}
+
+	return resourceGns3VpcsRead(d, meta)
+}
+
+func resourceGns3VpcsDelete(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*ProviderConfig)
+	host := config.Host
+	projectID := d.Get("project_id").(string)
+	nodeID := d.Id()
+
+	url := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete request for VPCS node: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete VPCS node: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete VPCS node, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceGns3VpcsImporter(
+	ctx context.Context,
+	d *schema.ResourceData,
+	meta interface{},
+) ([]*schema.ResourceData, error) {
+	raw := d.Id()
+	var projectID, nodeID string
+
+	if parts := strings.SplitN(raw, "/", 2); len(parts) == 2 {
+		projectID = parts[0]
+		nodeID = parts[1]
+	} else {
+		return nil, fmt.Errorf("invalid import ID %q — expected format <project_id>/<node_id>", raw)
+	}
+
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	d.SetId(nodeID)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -1,9 +1,18 @@
 package provider
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Fetch the first available project ID (used by both nodes and links)
@@ -25,27 +34,283 @@ func getProjectID(host string) (string, error) {
 	return projects[0]["project_id"].(string), nil
 }
 
-// Function to get template ID from template name
-func getTemplateID(host string, templateName string) (string, error) {
+// listTemplates fetches every template known to the controller.
+func listTemplates(host string) ([]map[string]interface{}, error) {
 	resp, err := http.Get(fmt.Sprintf("%s/v2/templates", host))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list templates, status code: %d, response: %s", resp.StatusCode, string(body))
+	}
+
 	var templates []map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Function to get template ID from template name
+func getTemplateID(host string, templateName string) (string, error) {
+	templates, err := listTemplates(host)
+	if err != nil {
 		return "", err
 	}
 
+	// GNS3 allows several templates with the same name, so keep scanning after a match
+	found := ""
 	for _, template := range templates {
-		if template["name"].(string) == templateName {
-			if id, ok := template["template_id"].(string); ok {
-				return id, nil
-			} else if id, ok := template["id"].(string); ok {
-				return id, nil
+		if name, _ := template["name"].(string); name == templateName {
+			id, ok := template["template_id"].(string)
+			if !ok {
+				id, ok = template["id"].(string)
+			}
+			if !ok {
+				continue
+			}
+			if found != "" {
+				return "", fmt.Errorf("more than one template is named '%s'; set template_id instead", templateName)
+			}
+			found = id
+		}
+	}
+	if found == "" {
+		return "", fmt.Errorf("template %s not found", templateName)
+	}
+	return found, nil
+}
+
+// getNode fetches a node from the controller. found is false when the node no longer exists.
+func getNode(host, projectID, nodeID string) (map[string]interface{}, bool, error) {
+	apiURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	resp, err := http.Get(apiURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read node %s: %s", nodeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, false, fmt.Errorf("failed to read node %s, status: %d, response: %s", nodeID, resp.StatusCode, string(body))
+	}
+
+	var node map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&node); err != nil {
+		return nil, false, fmt.Errorf("failed to decode node %s: %s", nodeID, err)
+	}
+	return node, true, nil
+}
+
+// nodeAction posts a lifecycle action (start, stop, suspend, reload) to a node.
+func nodeAction(host, projectID, nodeID, action string) error {
+	apiURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s/%s", host, projectID, nodeID, action)
+	resp, err := http.Post(apiURL, "application/json", bytes.NewBuffer([]byte("{}")))
+	if err != nil {
+		return fmt.Errorf("failed to %s node %s: %s", action, nodeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to %s node %s, status: %d, response: %s", action, nodeID, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// waitForNodeStatus polls a node until it reports the wanted status or the timeout expires.
+func waitForNodeStatus(host, projectID, nodeID, status string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		node, found, err := getNode(host, projectID, nodeID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("node %s not found while waiting for status %q", nodeID, status)
+		}
+		if s, _ := node["status"].(string); s == status {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for node %s to become %s", timeout, nodeID, status)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// consoleAddress returns the host:port of a node's console. GNS3 reports wildcard
+// console hosts for locally bound consoles, in which case the provider host is used.
+func consoleAddress(host string, node map[string]interface{}) (string, error) {
+	port, ok := node["console"].(float64)
+	if !ok || port == 0 {
+		return "", fmt.Errorf("node %v has no console port", node["name"])
+	}
+
+	consoleHost, _ := node["console_host"].(string)
+	switch consoleHost {
+	case "", "0.0.0.0", "::", "0:0:0:0:0:0:0:0":
+		u, err := url.Parse(host)
+		if err != nil {
+			return "", fmt.Errorf("invalid provider host %q: %s", host, err)
+		}
+		consoleHost = u.Hostname()
+	}
+
+	return net.JoinHostPort(consoleHost, strconv.Itoa(int(port))), nil
+}
+
+// nodeStatusSchema returns the desired status attribute shared by node resources.
+func nodeStatusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{"started", "stopped", "suspended"}, false),
+		Description:  "Desired node status (started, stopped or suspended). Read back from the node, so a node that stopped or crashed is started again on the next apply.",
+	}
+}
+
+// builtinNodeStatusSchema returns the status attribute of builtin nodes (switches,
+// hubs, clouds, NAT). GNS3 always reports them as started and ignores start and
+// stop, so the status is only read back.
+func builtinNodeStatusSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Node status as reported by GNS3. Builtin nodes are always started.",
+	}
+}
+
+// desiredNodeStatus returns the configured status, falling back to a deprecated
+// boolean start flag. An empty string means no status was requested.
+func desiredNodeStatus(d *schema.ResourceData, legacyStartKey string) string {
+	if v := d.GetRawConfig().GetAttr("status"); v.IsKnown() && !v.IsNull() {
+		return v.AsString()
+	}
+	if legacyStartKey != "" && d.Get(legacyStartKey).(bool) {
+		return "started"
+	}
+	return ""
+}
+
+// setNodeStatus drives a node from its current status to the desired one.
+func setNodeStatus(host, projectID, nodeID, current, desired string) error {
+	if desired == "" || desired == current {
+		return nil
+	}
+
+	switch desired {
+	case "started":
+		return nodeAction(host, projectID, nodeID, "start")
+	case "stopped":
+		return nodeAction(host, projectID, nodeID, "stop")
+	case "suspended":
+		// Only running nodes can be suspended
+		if current != "started" {
+			if err := nodeAction(host, projectID, nodeID, "start"); err != nil {
+				return err
 			}
 		}
+		return nodeAction(host, projectID, nodeID, "suspend")
+	}
+	return fmt.Errorf("unsupported node status %q", desired)
+}
+
+// updateNode sends a PUT with the given top-level fields (name, x, y, properties...) to a node.
+func updateNode(host, projectID, nodeID string, payload map[string]interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal node update: %s", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/v2/projects/%s/nodes/%s", host, projectID, nodeID)
+	req, err := http.NewRequest("PUT", apiURL, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create PUT request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update node %s: %s", nodeID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update node %s, status: %d, response: %s", nodeID, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// updateNodeProperties merges the given properties into a node through the controller.
+func updateNodeProperties(host, projectID, nodeID string, properties map[string]interface{}) error {
+	return updateNode(host, projectID, nodeID, map[string]interface{}{"properties": properties})
+}
+
+// setNodeProperties copies scalar node properties into same-named resource attributes.
+func setNodeProperties(d *schema.ResourceData, props map[string]interface{}, keys []string) {
+	for _, key := range keys {
+		switch v := props[key].(type) {
+		case float64:
+			d.Set(key, int(v))
+		case string, bool:
+			d.Set(key, v)
+		case nil:
+			d.Set(key, nil)
+		}
+	}
+}
+
+// coercePropertyValue converts a string from a Terraform map to the type of the existing
+// property value, so "1024" is sent as a number and "true" as a bool.
+func coercePropertyValue(existing interface{}, raw string) (interface{}, error) {
+	switch existing.(type) {
+	case float64:
+		if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", raw)
+		}
+		return f, nil
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected a bool, got %q", raw)
+		}
+		return b, nil
+	case string:
+		return raw, nil
+	}
+	// Lists, objects and unset values are taken as JSON when they parse, else as plain strings
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err == nil {
+		return decoded, nil
+	}
+	return raw, nil
+}
+
+// formatPropertyValue renders a property value as a string for Terraform maps.
+func formatPropertyValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
 	}
-	return "", fmt.Errorf("template %s not found", templateName)
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
 }